@@ -0,0 +1,95 @@
+package exception
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// AuditActorField is the field whose value is reported as the actor of an audit event.
+const AuditActorField = "actor"
+
+// AuditEvent describes the creation of an error with an audited code or domain,
+// or the wrapping of an error into one.
+type AuditEvent struct {
+	Time    time.Time
+	ID      string
+	Code    ErrorCode
+	Domain  string
+	Message string
+	Actor   string
+	Origin  Frame
+	Fields  map[string]any
+}
+
+// AuditSink receives audit events. Implementations must be safe for concurrent use.
+type AuditSink interface {
+	Audit(event AuditEvent)
+}
+
+// AuditSinkFunc adapts a function to an AuditSink.
+type AuditSinkFunc func(event AuditEvent)
+
+func (f AuditSinkFunc) Audit(event AuditEvent) {
+	f(event)
+}
+
+var (
+	auditMu      sync.RWMutex
+	auditSink    AuditSink
+	auditCodes   = make(map[ErrorCode]struct{})
+	auditDomains = make(map[string]struct{})
+)
+
+// SetAuditSink sets the sink receiving audit events; nil disables auditing.
+func SetAuditSink(sink AuditSink) {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	auditSink = sink
+}
+
+// AuditCodes marks codes as audited: creating an error with one of them, or
+// wrapping an error with one of them as its new code, emits an audit event.
+func AuditCodes(codes ...ErrorCode) {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	for _, code := range codes {
+		auditCodes[code] = struct{}{}
+	}
+}
+
+// AuditDomains marks domains as audited: creating an error in one of them, or
+// wrapping an error into one of them, emits an audit event.
+func AuditDomains(domains ...string) {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	for _, domain := range domains {
+		auditDomains[domain] = struct{}{}
+	}
+}
+
+// audit emits an audit event for a local error that was just created or given a
+// new code or domain, if its code or domain is audited.
+func (e *CustomError) audit() {
+	auditMu.RLock()
+	sink := auditSink
+	_, codeAudited := auditCodes[e.code]
+	_, domainAudited := auditDomains[e.domain]
+	auditMu.RUnlock()
+	if sink == nil || e.remote || !(codeAudited || domainAudited && e.domain != "") {
+		return
+	}
+	event := AuditEvent{
+		Time:    time.Now(),
+		ID:      e.id,
+		Code:    e.code,
+		Domain:  e.domain,
+		Message: e.Error(),
+		Origin:  e.Origin(),
+		Fields:  e.Fields(),
+	}
+	if actor, ok := e.fields[AuditActorField]; ok {
+		event.Actor = fmt.Sprint(actor)
+	}
+	sink.Audit(event)
+}
@@ -0,0 +1,81 @@
+package exception
+
+import (
+	"io"
+	"testing"
+)
+
+// recordAudits audits the given codes and domains for the test and records the
+// emitted events.
+func recordAudits(t *testing.T, codes []ErrorCode, domains []string) *[]AuditEvent {
+	t.Helper()
+	auditMu.Lock()
+	prevCodes, prevDomains := auditCodes, auditDomains
+	auditCodes, auditDomains = make(map[ErrorCode]struct{}), make(map[string]struct{})
+	auditMu.Unlock()
+	AuditCodes(codes...)
+	AuditDomains(domains...)
+	var events []AuditEvent
+	SetAuditSink(AuditSinkFunc(func(event AuditEvent) { events = append(events, event) }))
+	t.Cleanup(func() {
+		SetAuditSink(nil)
+		auditMu.Lock()
+		auditCodes, auditDomains = prevCodes, prevDomains
+		auditMu.Unlock()
+	})
+	return &events
+}
+
+const errorForbidden ErrorCode = 403
+
+func TestAudit(t *testing.T) {
+	tests := []struct {
+		name   string
+		create func() error
+		want   int
+	}{
+		{"new with audited code", func() error { return New("denied", errorForbidden) }, 1},
+		{"new with other code", func() error { return New("missing", ErrorDataNotFound) }, 0},
+		{"new in audited domain", func() error { return New("declined", ErrorDataInvalid, WithDomain("billing")) }, 1},
+		{"wrap with audited code", func() error { return Wrap(io.EOF, WithCode(errorForbidden)) }, 1},
+		{"builder", func() error { return Build("denied").Code(errorForbidden).Err() }, 1},
+		{"wrap without change", func() error { return Wrap(New("missing", ErrorDataNotFound)) }, 0},
+		{"re-coded", func() error { return WrapMessageWithCode(New("missing", ErrorDataNotFound), errorForbidden, "denied") }, 1},
+		{"moved to audited domain", func() error { return Wrap(New("missing", ErrorDataNotFound), WithDomain("billing")) }, 1},
+		{"remote", func() error { _, err := Decode([]byte(`{"code": 403, "message": "denied"}`)); return err }, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := recordAudits(t, []ErrorCode{errorForbidden}, []string{"billing"})
+			if err := tt.create(); err == nil && tt.want > 0 {
+				t.Fatal("create returned nil")
+			}
+			if len(*events) != tt.want {
+				t.Fatalf("events = %+v, want %d", *events, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditEvent(t *testing.T) {
+	events := recordAudits(t, []ErrorCode{errorForbidden}, nil)
+	err := New("denied", errorForbidden, WithField(AuditActorField, "alice"), WithDomain("users"))
+	Wrap(err)
+	if len(*events) != 1 {
+		t.Fatalf("events = %+v, want one for the creation only", *events)
+	}
+	event := (*events)[0]
+	if event.ID != err.ID() || event.Code != errorForbidden || event.Domain != "users" || event.Message != "denied" || event.Actor != "alice" {
+		t.Fatalf("event = %+v", event)
+	}
+	if event.Time.IsZero() || event.Fields[AuditActorField] != "alice" {
+		t.Fatalf("event = %+v", event)
+	}
+}
+
+func TestAuditWithoutSink(t *testing.T) {
+	recordAudits(t, []ErrorCode{errorForbidden}, nil)
+	SetAuditSink(nil)
+	// 싱크가 없으면 아무 일도 일어나지 않아야 함
+	New("denied", errorForbidden)
+}
@@ -0,0 +1,66 @@
+package exception
+
+import "time"
+
+// Builder builds a CustomError attribute by attribute.
+//
+//	err := exception.Build("user not found").Code(exception.ErrorUserNotFound).Field("id", id).Err()
+type Builder struct {
+	err *CustomError
+}
+
+// Build starts building a CustomError with the given message.
+func Build(msg string) *Builder {
+	return &Builder{err: &CustomError{id: newID(), created: time.Now(), Message: msg, code: ErrorInternalServer}}
+}
+
+// Code sets the error code. The default is ErrorInternalServer.
+func (b *Builder) Code(code ErrorCode) *Builder {
+	b.err.code = code
+	return b
+}
+
+// Field attaches a key-value pair to the error.
+func (b *Builder) Field(key string, value any) *Builder {
+	b.err.setField(key, value)
+	return b
+}
+
+// Hint sets a remediation hint, such as what the caller should check.
+func (b *Builder) Hint(hint string) *Builder {
+	b.err.hint = hint
+	return b
+}
+
+// Public sets the message that is safe to show to end users.
+func (b *Builder) Public(msg string) *Builder {
+	b.err.publicMessage = msg
+	return b
+}
+
+// Cause sets the error that caused the built error.
+func (b *Builder) Cause(err error) *Builder {
+	b.err.Err = err
+	return b
+}
+
+// NoTrace skips trace capture when the error is built.
+func (b *Builder) NoTrace() *Builder {
+	b.err.noTrace = true
+	return b
+}
+
+// Err returns the built error with the caller's trace captured, unless NoTrace was called.
+// The builder must not be used afterwards.
+func (b *Builder) Err() error {
+	return b.build()
+}
+
+func (b *Builder) build() *CustomError {
+	e := b.err
+	if !e.noTrace {
+		e.pushTrace(captureStackTrace(0, 0))
+	}
+	e.finish()
+	return e
+}
@@ -0,0 +1,52 @@
+package exception
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func() error
+		check func(*CustomError) bool
+	}{
+		{"defaults", func() error { return Build("boom").Err() }, func(e *CustomError) bool {
+			return e.Message == "boom" && e.Code() == ErrorInternalServer && e.Err == nil
+		}},
+		{"code", func() error { return Build("user missing").Code(ErrorUserNotFound).Err() }, func(e *CustomError) bool {
+			return e.Code() == ErrorUserNotFound
+		}},
+		{"fields", func() error { return Build("boom").Field("id", 7).Field("tenant", "acme").Err() }, func(e *CustomError) bool {
+			id, _ := e.Field("id")
+			tenant, _ := e.Field("tenant")
+			return id == 7 && tenant == "acme"
+		}},
+		{"hint", func() error { return Build("boom").Hint("check the credentials").Err() }, func(e *CustomError) bool {
+			return e.Hint() == "check the credentials"
+		}},
+		{"hint option", func() error { return Wrap(io.EOF, WithHint("check the credentials")) }, func(e *CustomError) bool {
+			return e.Hint() == "check the credentials"
+		}},
+		{"public", func() error { return Build("db down").Public("try again later").Err() }, func(e *CustomError) bool {
+			return e.PublicMessage() == "try again later" && e.Error() == "db down"
+		}},
+		{"cause", func() error { return Build("read failed").Cause(io.EOF).Err() }, func(e *CustomError) bool {
+			return errors.Is(e, io.EOF)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.build()
+			customErr, ok := err.(*CustomError)
+			if !ok || !tt.check(customErr) {
+				t.Fatalf("built error = %#v", err)
+			}
+			if frame := parseFrame(customErr.Trace); !strings.HasSuffix(frame.File, "builder_test.go") || !strings.HasPrefix(frame.Function, "github.com/tae2089/exception.TestBuilder.") {
+				t.Fatalf("trace = %q, want the Err caller", customErr.Trace)
+			}
+		})
+	}
+}
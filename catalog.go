@@ -0,0 +1,206 @@
+package exception
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// CodeInfo describes a code registered in the catalog.
+type CodeInfo struct {
+	Code ErrorCode
+	// Name identifies the code, e.g. in logs, API documentation and Temporal error types.
+	Name string
+	// Domain is the domain the code belongs to, if any.
+	Domain string
+	// Message is the default message for errors with the code created without
+	// one; see DefaultMessage.
+	Message string
+	// PublicMessage is the default message shown to end users; see
+	// CustomError.PublicMessage.
+	PublicMessage string
+	// Description documents when the code is used.
+	Description string
+	// HTTPStatus, if not zero, is the status of responses for the code instead
+	// of the code itself; see HTTPStatus.
+	HTTPStatus int
+	// GRPCCode, if set, is the name of the gRPC status code for the code, such
+	// as "NOT_FOUND", used instead of the default mapping.
+	GRPCCode string
+	// HelpURL links to documentation about the code; it is included in error
+	// responses.
+	HelpURL string
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []CodeInfo
+	// registryIndex maps each code to its first entry in registry, the one
+	// LookupCode returns.
+	registryIndex = make(map[ErrorCode]int)
+)
+
+// RegisterCode adds a code to the catalog. Registering a code again adds another
+// entry; LookupCode returns the first one.
+func RegisterCode(info CodeInfo) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registryIndex[info.Code]; !ok {
+		registryIndex[info.Code] = len(registry)
+	}
+	registry = append(registry, info)
+}
+
+// LookupCode returns the first catalog entry registered for code.
+func LookupCode(code ErrorCode) (CodeInfo, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	if i, ok := registryIndex[code]; ok {
+		return registry[i], true
+	}
+	return CodeInfo{}, false
+}
+
+// DefaultMessage returns the message of errors with code created without one:
+// the message registered in the catalog or, for codes without one, the lowercase
+// HTTP status text such as "not found", or "unknown error".
+func DefaultMessage(code ErrorCode) string {
+	if info, ok := LookupCode(code); ok && info.Message != "" {
+		return info.Message
+	}
+	if text := http.StatusText(int(code)); text != "" {
+		return strings.ToLower(text)
+	}
+	return "unknown error"
+}
+
+// Codes returns the catalog entries ordered by code, in registration order for
+// equal codes.
+func Codes() []CodeInfo {
+	registryMu.RLock()
+	infos := slices.Clone(registry)
+	registryMu.RUnlock()
+	slices.SortStableFunc(infos, func(a, b CodeInfo) int {
+		return int(a.Code) - int(b.Code)
+	})
+	return infos
+}
+
+var domains = make(map[string]struct{})
+
+// RegisterDomain registers domains codes may belong to. Once a domain is
+// registered, ValidateCatalog reports codes outside the registered domains.
+func RegisterDomain(names ...string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, name := range names {
+		domains[name] = struct{}{}
+	}
+}
+
+// CatalogProblemKind classifies catalog problems.
+type CatalogProblemKind string
+
+const (
+	ProblemDuplicateCode  CatalogProblemKind = "duplicate code"
+	ProblemDuplicateName  CatalogProblemKind = "duplicate name"
+	ProblemMissingName    CatalogProblemKind = "missing name"
+	ProblemUnknownDomain  CatalogProblemKind = "unknown domain"
+	ProblemMissingMessage CatalogProblemKind = "missing default message"
+	ProblemHTTPStatus     CatalogProblemKind = "invalid HTTP status"
+	ProblemGRPCCode       CatalogProblemKind = "unknown gRPC code"
+)
+
+// grpcCodes are the names of the gRPC status codes, as in google.rpc.Code.
+var grpcCodes = []string{
+	"OK", "CANCELLED", "UNKNOWN", "INVALID_ARGUMENT", "DEADLINE_EXCEEDED", "NOT_FOUND",
+	"ALREADY_EXISTS", "PERMISSION_DENIED", "RESOURCE_EXHAUSTED", "FAILED_PRECONDITION",
+	"ABORTED", "OUT_OF_RANGE", "UNIMPLEMENTED", "INTERNAL", "UNAVAILABLE", "DATA_LOSS",
+	"UNAUTHENTICATED",
+}
+
+// CatalogProblem is a problem found in a catalog entry.
+type CatalogProblem struct {
+	Kind CatalogProblemKind
+	Info CodeInfo
+}
+
+func (p CatalogProblem) String() string {
+	return fmt.Sprintf("code %d (%q): %s", p.Info.Code, p.Info.Name, p.Kind)
+}
+
+// CatalogReport lists the problems found by ValidateCatalog.
+type CatalogReport struct {
+	Problems []CatalogProblem
+}
+
+// Err returns the report as an error, or nil when no problem was found.
+func (r *CatalogReport) Err() error {
+	if len(r.Problems) == 0 {
+		return nil
+	}
+	return r
+}
+
+func (r *CatalogReport) Error() string {
+	lines := make([]string, len(r.Problems))
+	for i, p := range r.Problems {
+		lines[i] = p.String()
+	}
+	return fmt.Sprintf("invalid error catalog: %d problem(s):\n%s", len(lines), strings.Join(lines, "\n"))
+}
+
+// ValidateCatalog checks the registered codes for duplicate codes and names,
+// missing names and default messages, domains that are not registered, HTTP
+// statuses outside 100-599 and unknown gRPC code names.
+func ValidateCatalog() *CatalogReport {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return validateCatalog(registry, domains)
+}
+
+// validateCatalog checks registry against the registered domains; see
+// ValidateCatalog.
+func validateCatalog(registry []CodeInfo, domains map[string]struct{}) *CatalogReport {
+	report := &CatalogReport{}
+	add := func(kind CatalogProblemKind, info CodeInfo) {
+		report.Problems = append(report.Problems, CatalogProblem{Kind: kind, Info: info})
+	}
+	seenCodes := make(map[ErrorCode]bool)
+	seenNames := make(map[string]bool)
+	for _, info := range registry {
+		if seenCodes[info.Code] {
+			add(ProblemDuplicateCode, info)
+		}
+		seenCodes[info.Code] = true
+		if info.Name == "" {
+			add(ProblemMissingName, info)
+		} else if seenNames[info.Name] {
+			add(ProblemDuplicateName, info)
+		}
+		seenNames[info.Name] = true
+		if _, ok := domains[info.Domain]; len(domains) > 0 && !ok {
+			add(ProblemUnknownDomain, info)
+		}
+		if info.Message == "" {
+			add(ProblemMissingMessage, info)
+		}
+		if info.HTTPStatus != 0 && (info.HTTPStatus < 100 || info.HTTPStatus > 599) {
+			add(ProblemHTTPStatus, info)
+		}
+		if info.GRPCCode != "" && !slices.Contains(grpcCodes, info.GRPCCode) {
+			add(ProblemGRPCCode, info)
+		}
+	}
+	return report
+}
+
+// MustValidateCatalog panics if ValidateCatalog finds a problem. It is meant to be
+// called from init or main once all codes are registered.
+func MustValidateCatalog() {
+	if err := ValidateCatalog().Err(); err != nil {
+		panic(err)
+	}
+}
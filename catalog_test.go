@@ -0,0 +1,169 @@
+package exception
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// resetCatalog empties the catalog for the duration of the test.
+func resetCatalog(t *testing.T) {
+	t.Helper()
+	registryMu.Lock()
+	savedRegistry, savedIndex, savedDomains := registry, registryIndex, domains
+	registry, registryIndex, domains = nil, make(map[ErrorCode]int), make(map[string]struct{})
+	registryMu.Unlock()
+	t.Cleanup(func() {
+		registryMu.Lock()
+		registry, registryIndex, domains = savedRegistry, savedIndex, savedDomains
+		registryMu.Unlock()
+	})
+}
+
+func TestLookupCode(t *testing.T) {
+	resetCatalog(t)
+	RegisterCode(CodeInfo{Code: 40401, Name: "OrderNotFound", Message: "order not found"})
+	RegisterCode(CodeInfo{Code: 40901, Name: "OrderExists", Message: "order exists"})
+	RegisterCode(CodeInfo{Code: 40401, Name: "OrderMissing", Message: "order missing"})
+	tests := []struct {
+		code ErrorCode
+		name string
+		ok   bool
+	}{
+		{40401, "OrderNotFound", true},
+		{40901, "OrderExists", true},
+		{40402, "", false},
+	}
+	for _, tt := range tests {
+		info, ok := LookupCode(tt.code)
+		if ok != tt.ok || info.Name != tt.name {
+			t.Errorf("LookupCode(%d) = %+v, %v, want %q, %v", tt.code, info, ok, tt.name, tt.ok)
+		}
+	}
+	names := make([]string, 0, 3)
+	for _, info := range Codes() {
+		names = append(names, info.Name)
+	}
+	if want := []string{"OrderNotFound", "OrderMissing", "OrderExists"}; !slices.Equal(names, want) {
+		t.Fatalf("Codes = %v, want %v", names, want)
+	}
+}
+
+func TestCodeNameFromCatalog(t *testing.T) {
+	resetCatalog(t)
+	RegisterCode(CodeInfo{Code: 40401, Name: "OrderNotFound"})
+	RegisterCode(CodeInfo{Code: 40402, Message: "no name"})
+	tests := []struct {
+		code ErrorCode
+		want string
+	}{
+		{40401, "OrderNotFound"},
+		{40402, "Code40402"},
+		{404, "NotFound"},
+	}
+	for _, tt := range tests {
+		if got := tt.code.Name(); got != tt.want {
+			t.Errorf("ErrorCode(%d).Name() = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultMessage(t *testing.T) {
+	resetCatalog(t)
+	RegisterCode(CodeInfo{Code: 40401, Name: "OrderNotFound", Message: "order not found"})
+	RegisterCode(CodeInfo{Code: 40402, Name: "OrderGone"})
+	tests := []struct {
+		code ErrorCode
+		want string
+	}{
+		{40401, "order not found"},
+		{40402, "unknown error"},
+		{ErrorNotFound, "not found"},
+		{ErrorInternalServer, "internal server error"},
+		{0, "unknown error"},
+	}
+	for _, tt := range tests {
+		if got := DefaultMessage(tt.code); got != tt.want {
+			t.Errorf("DefaultMessage(%d) = %q, want %q", tt.code, got, tt.want)
+		}
+		if got := New("", tt.code).Error(); got != tt.want {
+			t.Errorf("New(\"\", %d).Error() = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestPublicMessageFromCatalog(t *testing.T) {
+	resetCatalog(t)
+	RegisterCode(CodeInfo{Code: 40401, Name: "OrderNotFound", PublicMessage: "We could not find that order."})
+	tests := []struct {
+		name string
+		err  *CustomError
+		want string
+	}{
+		{"catalog", New("order 7 missing", 40401), "We could not find that order."},
+		{"explicit", New("order 7 missing", 40401, WithPublicMessage("Gone.")), "Gone."},
+		{"unregistered", New("boom", ErrorInternalServer), ""},
+	}
+	for _, tt := range tests {
+		if got := tt.err.PublicMessage(); got != tt.want {
+			t.Errorf("%s: PublicMessage = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateCatalog(t *testing.T) {
+	valid := CodeInfo{Code: 40201, Name: "PaymentDeclined", Domain: "billing", Message: "declined"}
+	tests := []struct {
+		name    string
+		domains []string
+		infos   []CodeInfo
+		want    map[CatalogProblemKind]int
+	}{
+		{"valid", []string{"billing"}, []CodeInfo{valid}, map[CatalogProblemKind]int{}},
+		{"duplicate", nil, []CodeInfo{valid, valid}, map[CatalogProblemKind]int{ProblemDuplicateCode: 1, ProblemDuplicateName: 1}},
+		{"missing fields", nil, []CodeInfo{{Code: 40202}}, map[CatalogProblemKind]int{ProblemMissingName: 1, ProblemMissingMessage: 1}},
+		{"domains not registered", nil, []CodeInfo{{Code: 40202, Name: "Lost", Domain: "shipping", Message: "lost"}}, map[CatalogProblemKind]int{}},
+		{"unknown domain", []string{"billing"}, []CodeInfo{valid, {Code: 40202, Name: "Lost", Domain: "shipping", Message: "lost"}},
+			map[CatalogProblemKind]int{ProblemUnknownDomain: 1}},
+		{"mappings", nil, []CodeInfo{{Code: 40202, Name: "Lost", Message: "lost", HTTPStatus: 404, GRPCCode: "NOT_FOUND"}}, map[CatalogProblemKind]int{}},
+		{"invalid mappings", nil, []CodeInfo{{Code: 40202, Name: "Lost", Message: "lost", HTTPStatus: 4040, GRPCCode: "NotFound"}},
+			map[CatalogProblemKind]int{ProblemHTTPStatus: 1, ProblemGRPCCode: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetCatalog(t)
+			RegisterDomain(tt.domains...)
+			for _, info := range tt.infos {
+				RegisterCode(info)
+			}
+			report := ValidateCatalog()
+			kinds := make(map[CatalogProblemKind]int)
+			for _, p := range report.Problems {
+				kinds[p.Kind]++
+			}
+			if !maps.Equal(kinds, tt.want) {
+				t.Fatalf("problems = %v, want %v", kinds, tt.want)
+			}
+			if err := report.Err(); (err != nil) != (len(tt.want) > 0) {
+				t.Fatalf("Err = %v", err)
+			}
+		})
+	}
+}
+
+func TestMustValidateCatalog(t *testing.T) {
+	resetCatalog(t)
+	RegisterCode(CodeInfo{Code: 40201, Name: "PaymentDeclined", Message: "declined"})
+	MustValidateCatalog()
+
+	RegisterCode(CodeInfo{Code: 40201, Name: "PaymentRefused", Message: "refused"})
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || !strings.Contains(err.Error(), `code 40201 ("PaymentRefused"): duplicate code`) {
+			t.Fatalf("recovered %v", err)
+		}
+	}()
+	MustValidateCatalog()
+	t.Fatal("MustValidateCatalog should panic")
+}
@@ -0,0 +1,128 @@
+package exception
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+)
+
+// catalogDocument is the document read by LoadCatalog.
+type catalogDocument struct {
+	Domains []string       `json:"domains"`
+	Codes   []catalogEntry `json:"codes"`
+}
+
+type catalogEntry struct {
+	Code          ErrorCode `json:"code"`
+	Name          string    `json:"name"`
+	Domain        string    `json:"domain"`
+	Message       string    `json:"message"`
+	PublicMessage string    `json:"public_message"`
+	Description   string    `json:"description"`
+	HTTPStatus    int       `json:"http_status"`
+	GRPCCode      string    `json:"grpc_code"`
+	HelpURL       string    `json:"help_url"`
+	// Messages maps locales to localized public messages; see RegisterMessages.
+	Messages map[string]string `json:"messages"`
+}
+
+// LoadCatalog reads a JSON catalog document from r and registers its domains,
+// codes and localized messages, so messages and mappings can be changed without
+// recompiling. The document looks like:
+//
+//	{
+//	  "domains": ["users"],
+//	  "codes": [{
+//	    "code": 40401, "name": "UserNotFound", "domain": "users",
+//	    "message": "user not found", "public_message": "No such user.",
+//	    "http_status": 404, "grpc_code": "NOT_FOUND",
+//	    "help_url": "https://example.com/errors/user-not-found",
+//	    "messages": {"ko": "사용자를 찾을 수 없습니다."}
+//	  }]
+//	}
+//
+// Fields set for a code already in the catalog replace the registered ones,
+// leaving the others as they are. It should be called during initialization,
+// after the codes registered in code. The catalog with the document merged in is
+// validated first: if it has problems, the *CatalogReport is returned and nothing
+// is registered.
+func LoadCatalog(r io.Reader) error {
+	var doc catalogDocument
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("exception: invalid catalog document: %w", err)
+	}
+	seen := make(map[ErrorCode]bool, len(doc.Codes))
+	for i, entry := range doc.Codes {
+		if entry.Code == 0 {
+			return fmt.Errorf("exception: catalog entry %d has no code", i)
+		}
+		if seen[entry.Code] {
+			return fmt.Errorf("exception: catalog entry %d repeats code %d", i, entry.Code)
+		}
+		seen[entry.Code] = true
+	}
+
+	registryMu.Lock()
+	// 복사본에 병합하고 검증을 통과한 경우에만 반영
+	nextRegistry, nextIndex, nextDomains := slices.Clone(registry), maps.Clone(registryIndex), maps.Clone(domains)
+	for _, name := range doc.Domains {
+		nextDomains[name] = struct{}{}
+	}
+	for _, entry := range doc.Codes {
+		nextRegistry = mergeCode(nextRegistry, nextIndex, CodeInfo{
+			Code:          entry.Code,
+			Name:          entry.Name,
+			Domain:        entry.Domain,
+			Message:       entry.Message,
+			PublicMessage: entry.PublicMessage,
+			Description:   entry.Description,
+			HTTPStatus:    entry.HTTPStatus,
+			GRPCCode:      entry.GRPCCode,
+			HelpURL:       entry.HelpURL,
+		})
+	}
+	if err := validateCatalog(nextRegistry, nextDomains).Err(); err != nil {
+		registryMu.Unlock()
+		return err
+	}
+	registry, registryIndex, domains = nextRegistry, nextIndex, nextDomains
+	registryMu.Unlock()
+
+	for _, entry := range doc.Codes {
+		for locale, msg := range entry.Messages {
+			RegisterMessages(locale, map[ErrorCode]string{entry.Code: msg})
+		}
+	}
+	return nil
+}
+
+// mergeCode adds info to registry, or sets its non-zero fields on the entry
+// index points to if the code is already registered, and returns the registry.
+func mergeCode(registry []CodeInfo, index map[ErrorCode]int, info CodeInfo) []CodeInfo {
+	i, ok := index[info.Code]
+	if !ok {
+		index[info.Code] = len(registry)
+		return append(registry, info)
+	}
+	entry := &registry[i]
+	set := func(dst *string, src string) {
+		if src != "" {
+			*dst = src
+		}
+	}
+	set(&entry.Name, info.Name)
+	set(&entry.Domain, info.Domain)
+	set(&entry.Message, info.Message)
+	set(&entry.PublicMessage, info.PublicMessage)
+	set(&entry.Description, info.Description)
+	set(&entry.GRPCCode, info.GRPCCode)
+	set(&entry.HelpURL, info.HelpURL)
+	if info.HTTPStatus != 0 {
+		entry.HTTPStatus = info.HTTPStatus
+	}
+	return registry
+}
@@ -0,0 +1,123 @@
+package exception
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const catalogJSON = `{
+  "domains": ["users"],
+  "codes": [
+    {
+      "code": 40401, "name": "UserNotFound", "domain": "users",
+      "message": "user not found", "public_message": "No such user.",
+      "http_status": 404, "grpc_code": "NOT_FOUND",
+      "help_url": "https://example.com/errors/user-not-found",
+      "messages": {"ko": "사용자를 찾을 수 없습니다."}
+    },
+    {"code": 40901, "message": "user already exists"}
+  ]
+}`
+
+func TestLoadCatalog(t *testing.T) {
+	resetCatalog(t)
+	resetLocales(t)
+	RegisterCode(CodeInfo{Code: 40901, Name: "UserExists", Domain: "users", Message: "user exists", Description: "registered in code"})
+	if err := LoadCatalog(strings.NewReader(catalogJSON)); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		code ErrorCode
+		want CodeInfo
+	}{
+		{40401, CodeInfo{Code: 40401, Name: "UserNotFound", Domain: "users", Message: "user not found", PublicMessage: "No such user.",
+			HTTPStatus: 404, GRPCCode: "NOT_FOUND", HelpURL: "https://example.com/errors/user-not-found"}},
+		// 코드로 등록한 항목에 문서의 값만 덮어씀
+		{40901, CodeInfo{Code: 40901, Name: "UserExists", Domain: "users", Message: "user already exists", Description: "registered in code"}},
+	}
+	for _, tt := range tests {
+		if info, ok := LookupCode(tt.code); !ok || info != tt.want {
+			t.Errorf("LookupCode(%d) = %+v, want %+v", tt.code, info, tt.want)
+		}
+	}
+	if n := len(Codes()); n != 2 {
+		t.Fatalf("%d codes registered, want 2", n)
+	}
+	if msg, ok := LocalizedMessage(40401, "ko"); !ok || msg != "사용자를 찾을 수 없습니다." {
+		t.Fatalf("LocalizedMessage = %q, %v", msg, ok)
+	}
+	if status := HTTPStatus(40401); status != http.StatusNotFound {
+		t.Fatalf("HTTPStatus = %d", status)
+	}
+
+	rec := httptest.NewRecorder()
+	WriteHTTP(rec, New("", 40401))
+	var body responseBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusNotFound || body.Message != "No such user." || body.HelpURL != "https://example.com/errors/user-not-found" {
+		t.Fatalf("status = %d, body = %+v", rec.Code, body)
+	}
+}
+
+func TestLoadCatalogErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"malformed", `{"codes": [`, "invalid catalog document"},
+		{"unknown field", `{"codes": [{"code": 40401, "status": 404}]}`, "invalid catalog document"},
+		{"missing code", `{"codes": [{"name": "Lost"}]}`, "entry 0 has no code"},
+		{"repeated code", `{"codes": [{"code": 40401}, {"code": 40401}]}`, "entry 1 repeats code 40401"},
+		{"invalid catalog", `{"codes": [{"code": 40401, "name": "UserNotFound", "message": "not found", "grpc_code": "missing"}]}`, "unknown gRPC code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetCatalog(t)
+			err := LoadCatalog(strings.NewReader(tt.doc))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("LoadCatalog = %v, want %q", err, tt.want)
+			}
+		})
+	}
+	resetCatalog(t)
+	var report *CatalogReport
+	if err := LoadCatalog(strings.NewReader(`{"codes": [{"code": 40401}]}`)); !errors.As(err, &report) || len(report.Problems) != 2 {
+		t.Fatalf("LoadCatalog = %v, want a catalog report", err)
+	}
+}
+
+func TestLoadCatalogRejectedLeavesCatalog(t *testing.T) {
+	resetCatalog(t)
+	resetLocales(t)
+	RegisterCode(CodeInfo{Code: 40901, Name: "UserExists", Message: "user exists"})
+	before := Codes()
+	doc := `{
+	  "domains": ["users"],
+	  "codes": [
+	    {"code": 40901, "message": "user already exists", "http_status": 409},
+	    {"code": 40401, "name": "UserNotFound", "message": "not found", "grpc_code": "missing", "messages": {"ko": "없음"}}
+	  ]
+	}`
+	var report *CatalogReport
+	if err := LoadCatalog(strings.NewReader(doc)); !errors.As(err, &report) {
+		t.Fatalf("LoadCatalog = %v, want a catalog report", err)
+	}
+	if after := Codes(); !slices.Equal(after, before) {
+		t.Fatalf("Codes = %+v, want %+v", after, before)
+	}
+	if _, ok := LocalizedMessage(40401, "ko"); ok {
+		t.Fatal("localized messages of a rejected document should not be registered")
+	}
+	// 거부된 문서의 도메인도 등록되지 않아 도메인 검사가 꺼진 상태로 남음
+	if err := ValidateCatalog().Err(); err != nil {
+		t.Fatal(err)
+	}
+}
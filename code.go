@@ -0,0 +1,49 @@
+package exception
+
+import (
+	"net/http"
+	"strconv"
+	"unicode"
+)
+
+// Name returns the name registered for the code in the catalog or, for codes
+// without one, a CamelCase name derived from its HTTP status text, such as
+// "NotFound" for 404, or "Code<N>".
+func (c ErrorCode) Name() string {
+	if info, ok := LookupCode(c); ok && info.Name != "" {
+		return info.Name
+	}
+	if c == ErrorClientClosedRequest {
+		return "ClientClosedRequest"
+	}
+	text := http.StatusText(int(c))
+	name := make([]rune, 0, len(text))
+	upper := true
+	for _, r := range text {
+		switch {
+		case r == ' ' || r == '-':
+			upper = true
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if upper {
+				r = unicode.ToUpper(r)
+			}
+			name = append(name, r)
+			upper = false
+		}
+	}
+	if len(name) == 0 {
+		return "Code" + strconv.Itoa(int(c))
+	}
+	return string(name)
+}
+
+// isRetryableCode reports whether errors with the code are transient by default:
+// timeouts, rate limiting and unavailable upstreams.
+func isRetryableCode(code ErrorCode) bool {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
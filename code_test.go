@@ -0,0 +1,24 @@
+package exception
+
+import "testing"
+
+func TestErrorCodeName(t *testing.T) {
+	tests := []struct {
+		code ErrorCode
+		want string
+	}{
+		{ErrorUserNotFound, "NotFound"},
+		{ErrorInternalServer, "InternalServerError"},
+		{ErrorUserExists, "Conflict"},
+		{ErrorCode(418), "ImATeapot"},
+		{ErrorClientClosedRequest, "ClientClosedRequest"},
+		{ErrorCode(203), "NonAuthoritativeInformation"},
+		{ErrorCode(0), "Code0"},
+		{ErrorCode(999), "Code999"},
+	}
+	for _, tt := range tests {
+		if got := tt.code.Name(); got != tt.want {
+			t.Errorf("ErrorCode(%d).Name() = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
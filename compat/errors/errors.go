@@ -0,0 +1,150 @@
+// Package errors is a drop-in replacement for github.com/pkg/errors built on
+// exception.CustomError. Switching the import path keeps existing call sites
+// working while every error gains a code, an ID and the exception trace model;
+// use the exception package directly to set codes and fields.
+//
+// Wrapped errors keep the code of the first CustomError they wrap, and errors
+// created here get exception.ErrorInternalServer. As with pkg/errors, errors.Is
+// matches errors created here by identity, not by code. Errors print their traces
+// with %+v as pkg/errors errors do. The StackTrace and Frame types are not
+// provided; read traces with exception.Trace or CustomError.TracePoints.
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+
+	"github.com/tae2089/exception"
+)
+
+// New returns an error with the message and the caller's trace.
+func New(message string) error {
+	return traced(exception.New(message, exception.ErrorInternalServer))
+}
+
+// Errorf formats an error message with the caller's trace. Unlike pkg/errors, the
+// operand of a %w verb becomes the cause.
+func Errorf(format string, args ...any) error {
+	return traced(newf(format, args...))
+}
+
+func newf(format string, args ...any) *exception.CustomError {
+	formatted := fmt.Errorf(format, args...)
+	var cause error
+	switch u := formatted.(type) {
+	case interface{ Unwrap() error }:
+		cause = u.Unwrap()
+	case interface{ Unwrap() []error }:
+		cause = stderrors.Join(u.Unwrap()...)
+	}
+	return exception.New(formatted.Error(), codeOf(cause), exception.WithCause(cause))
+}
+
+// WithStack records the caller's trace on a new error wrapping err, with the same
+// message. It returns nil when err is nil.
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	return traced(exception.New(err.Error(), codeOf(err), exception.WithCause(err)))
+}
+
+// Wrap returns an error annotating err with the message and the caller's trace,
+// such that its message is "message: err". It returns nil when err is nil.
+func Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return traced(withMessage(err, message))
+}
+
+// Wrapf is like Wrap with a formatted message.
+func Wrapf(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return traced(withMessage(err, fmt.Sprintf(format, args...)))
+}
+
+// WithMessage annotates err with the message without recording a trace. It
+// returns nil when err is nil.
+func WithMessage(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return &compatError{withMessage(err, message)}
+}
+
+// WithMessagef is like WithMessage with a formatted message.
+func WithMessagef(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return &compatError{withMessage(err, fmt.Sprintf(format, args...))}
+}
+
+func withMessage(err error, message string) *exception.CustomError {
+	return exception.New(message+": "+err.Error(), codeOf(err), exception.WithCause(err))
+}
+
+// traced records the trace of the caller of the exported function on e, which
+// must not be shared yet, and returns it as a compatError.
+func traced(e *exception.CustomError) error {
+	_ = exception.Wrap(e, exception.WithCallerSkip(2))
+	return &compatError{e}
+}
+
+// compatError is the error returned by this package. It wraps the CustomError
+// instead of being one, so errors.Is matches it by identity as it does pkg/errors
+// errors: CustomError.Is matches any CustomError with the same code, which would
+// make every error created here match every other. Unwrap returns the
+// CustomError, so errors.As and the exception helpers find it.
+type compatError struct {
+	err *exception.CustomError
+}
+
+func (e *compatError) Error() string { return e.err.Error() }
+
+func (e *compatError) Unwrap() error { return e.err }
+
+// Cause returns the wrapped error, as pkg/errors errors do.
+func (e *compatError) Cause() error { return e.err.Cause() }
+
+// Format formats the error like exception.CustomError, printing the traces with %+v.
+func (e *compatError) Format(s fmt.State, verb rune) { e.err.Format(s, verb) }
+
+func codeOf(err error) exception.ErrorCode {
+	if code, ok := exception.CodeOf(err); ok {
+		return code
+	}
+	return exception.ErrorInternalServer
+}
+
+// Cause returns the underlying cause of err: the last error reached by calling
+// Cause methods, as pkg/errors does.
+func Cause(err error) error {
+	type causer interface {
+		Cause() error
+	}
+	for err != nil {
+		c, ok := err.(causer)
+		if !ok {
+			break
+		}
+		cause := c.Cause()
+		if cause == nil {
+			break
+		}
+		err = cause
+	}
+	return err
+}
+
+// Is reports whether any error in err's chain matches target; see errors.Is.
+func Is(err, target error) bool { return stderrors.Is(err, target) }
+
+// As finds the first error in err's chain that matches target; see errors.As.
+func As(err error, target any) bool { return stderrors.As(err, target) }
+
+// Unwrap returns the result of calling the Unwrap method on err; see errors.Unwrap.
+func Unwrap(err error) error { return stderrors.Unwrap(err) }
@@ -0,0 +1,149 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tae2089/exception"
+)
+
+var errSentinel = New("sentinel")
+
+func TestWrap(t *testing.T) {
+	notFound := exception.New("user missing", exception.ErrorNotFound)
+	tests := []struct {
+		name    string
+		err     error
+		message string
+		cause   error
+		code    exception.ErrorCode
+		traced  bool
+	}{
+		{"New", New("boom"), "boom", nil, exception.ErrorInternalServer, true},
+		{"Errorf", Errorf("load %s: %w", "user", io.EOF), "load user: EOF", io.EOF, exception.ErrorInternalServer, true},
+		{"Wrap", Wrap(io.EOF, "read"), "read: EOF", io.EOF, exception.ErrorInternalServer, true},
+		{"Wrapf", Wrapf(Wrap(io.EOF, "read"), "load %s", "user"), "load user: read: EOF", io.EOF, exception.ErrorInternalServer, true},
+		{"WithStack", WithStack(io.EOF), "EOF", io.EOF, exception.ErrorInternalServer, true},
+		{"WithStack keeps the code", WithStack(fmt.Errorf("load: %w", notFound)), "load: user missing", nil, exception.ErrorNotFound, true},
+		{"WithMessage", WithMessage(io.EOF, "read"), "read: EOF", io.EOF, exception.ErrorInternalServer, false},
+		{"WithMessagef", WithMessagef(io.EOF, "read %d", 7), "read 7: EOF", io.EOF, exception.ErrorInternalServer, false},
+		{"keeps the code", Wrap(notFound, "lookup"), "lookup: user missing", notFound, exception.ErrorNotFound, true},
+		{"keeps the sentinel", Wrap(errSentinel, "lookup"), "lookup: sentinel", errSentinel, exception.ErrorInternalServer, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err.Error() != tt.message {
+				t.Fatalf("Error = %q, want %q", tt.err.Error(), tt.message)
+			}
+			if tt.cause != nil && (Cause(tt.err) != tt.cause || !Is(tt.err, tt.cause)) {
+				t.Fatalf("Cause = %v, want %v", Cause(tt.err), tt.cause)
+			}
+			if code, _ := exception.CodeOf(tt.err); code != tt.code {
+				t.Fatalf("code = %d, want %d", code, tt.code)
+			}
+			if traced := exception.Trace(tt.err) != ""; traced != tt.traced {
+				t.Fatalf("traced = %v, want %v", traced, tt.traced)
+			}
+		})
+	}
+	if errSentinel.Error() != "sentinel" {
+		t.Fatalf("Wrap changed the sentinel: %q", errSentinel.Error())
+	}
+}
+
+func TestNilErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"Wrap", Wrap(nil, "x")},
+		{"Wrapf", Wrapf(nil, "x %d", 1)},
+		{"WithStack", WithStack(nil)},
+		{"WithMessage", WithMessage(nil, "x")},
+		{"WithMessagef", WithMessagef(nil, "x %d", 1)},
+		{"Cause", Cause(nil)},
+	}
+	for _, tt := range tests {
+		if tt.err != nil {
+			t.Errorf("%s(nil) = %v, want nil", tt.name, tt.err)
+		}
+	}
+}
+
+func TestFormatPrintsStack(t *testing.T) {
+	err := Wrap(New("boom"), "handle")
+	tests := []struct {
+		format string
+		prefix string
+		frames int
+	}{
+		{"%v", "handle: boom", 0},
+		{"%s", "handle: boom", 0},
+		{"%+v", "handle: boom\n", 2},
+	}
+	for _, tt := range tests {
+		got := fmt.Sprintf(tt.format, err)
+		if !strings.HasPrefix(got, tt.prefix) {
+			t.Errorf("%s = %q, want the prefix %q", tt.format, got, tt.prefix)
+		}
+		// %+v는 호출마다 테스트 함수의 프레임을 하나씩 출력
+		if n := strings.Count(got, "errors.TestFormatPrintsStack\n\t"); n != tt.frames {
+			t.Errorf("%s has %d frames of the test, want %d:\n%s", tt.format, n, tt.frames, got)
+		}
+	}
+}
+
+func TestIsMatchesByIdentity(t *testing.T) {
+	errConflict, errNotFound := New("conflict"), New("not found")
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{"same sentinel", errConflict, errConflict, true},
+		{"other sentinel", errConflict, errNotFound, false},
+		{"wrapped sentinel", Wrap(errConflict, "saving"), errConflict, true},
+		{"wrapped other sentinel", Wrap(errConflict, "saving"), errNotFound, false},
+		{"WithStack", WithStack(errConflict), errNotFound, false},
+		{"WithMessage", WithMessage(errConflict, "saving"), errConflict, true},
+		// exception 에러는 코드로 비교
+		{"code", Wrap(exception.New("user missing", exception.ErrorNotFound), "lookup"), exception.New("", exception.ErrorNotFound), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Is(tt.err, tt.target); got != tt.want {
+				t.Fatalf("Is(%v, %v) = %v, want %v", tt.err, tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapLeavesArgument(t *testing.T) {
+	sentinel := exception.New("sentinel", exception.ErrorConflict)
+	trace := exception.Trace(sentinel)
+	wraps := []func(error) error{
+		WithStack,
+		func(err error) error { return Wrap(err, "saving") },
+	}
+	// 같은 에러를 동시에 감싸도 경쟁 상태가 없어야 함
+	var wg sync.WaitGroup
+	for range 4 {
+		for _, wrap := range wraps {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := wrap(sentinel); err == error(sentinel) || Cause(err) != error(sentinel) {
+					t.Errorf("wrap returned %v, want a new error wrapping the argument", err)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	if exception.Trace(sentinel) != trace || sentinel.Error() != "sentinel" {
+		t.Fatalf("the argument changed: %q\n%s", sentinel.Error(), exception.Trace(sentinel))
+	}
+}
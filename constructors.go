@@ -0,0 +1,123 @@
+package exception
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// newTraced creates a new CustomError capturing the trace of the exported
+// constructor's caller.
+func newTraced(opts ...CustomErrorOption) *CustomError {
+	e := newCustomError(opts...)
+	e.pushTrace(captureStackTrace(0, 0))
+	e.finish()
+	return e
+}
+
+// NotFound creates a not found error with the caller's trace.
+func NotFound(msg string) *CustomError {
+	return newTraced(WithMessage(msg), WithCode(ErrorNotFound))
+}
+
+// NotFoundf creates a not found error with a formatted message and the caller's trace.
+func NotFoundf(format string, args ...any) *CustomError {
+	return newTraced(WithMessage(fmt.Sprintf(format, args...)), WithCode(ErrorNotFound))
+}
+
+// BadRequest creates a bad request error with the caller's trace.
+func BadRequest(msg string) *CustomError {
+	return newTraced(WithMessage(msg), WithCode(ErrorBadRequest))
+}
+
+// BadRequestf creates a bad request error with a formatted message and the caller's trace.
+func BadRequestf(format string, args ...any) *CustomError {
+	return newTraced(WithMessage(fmt.Sprintf(format, args...)), WithCode(ErrorBadRequest))
+}
+
+// Unauthorized creates an unauthorized error with the caller's trace.
+func Unauthorized(msg string) *CustomError {
+	return newTraced(WithMessage(msg), WithCode(ErrorUnAuthorized))
+}
+
+// Unauthorizedf creates an unauthorized error with a formatted message and the caller's trace.
+func Unauthorizedf(format string, args ...any) *CustomError {
+	return newTraced(WithMessage(fmt.Sprintf(format, args...)), WithCode(ErrorUnAuthorized))
+}
+
+// Forbidden creates a forbidden error with the caller's trace.
+func Forbidden(msg string) *CustomError {
+	return newTraced(WithMessage(msg), WithCode(ErrorForbidden))
+}
+
+// Forbiddenf creates a forbidden error with a formatted message and the caller's trace.
+func Forbiddenf(format string, args ...any) *CustomError {
+	return newTraced(WithMessage(fmt.Sprintf(format, args...)), WithCode(ErrorForbidden))
+}
+
+// Conflict creates a conflict error with the caller's trace.
+func Conflict(msg string) *CustomError {
+	return newTraced(WithMessage(msg), WithCode(ErrorConflict))
+}
+
+// Conflictf creates a conflict error with a formatted message and the caller's trace.
+func Conflictf(format string, args ...any) *CustomError {
+	return newTraced(WithMessage(fmt.Sprintf(format, args...)), WithCode(ErrorConflict))
+}
+
+// TooManyRequests creates a too many requests error with the caller's trace.
+func TooManyRequests(msg string) *CustomError {
+	return newTraced(WithMessage(msg), WithCode(ErrorTooManyRequests))
+}
+
+// TooManyRequestsf creates a too many requests error with a formatted message and the caller's trace.
+func TooManyRequestsf(format string, args ...any) *CustomError {
+	return newTraced(WithMessage(fmt.Sprintf(format, args...)), WithCode(ErrorTooManyRequests))
+}
+
+// Internal creates an internal server error with the caller's trace.
+func Internal(msg string) *CustomError {
+	return newTraced(WithMessage(msg), WithCode(ErrorInternalServer))
+}
+
+// Internalf creates an internal server error with a formatted message and the caller's trace.
+func Internalf(format string, args ...any) *CustomError {
+	return newTraced(WithMessage(fmt.Sprintf(format, args...)), WithCode(ErrorInternalServer))
+}
+
+// Unavailable creates a service unavailable error with the caller's trace.
+func Unavailable(msg string) *CustomError {
+	return newTraced(WithMessage(msg), WithCode(ErrorServiceUnavailable))
+}
+
+// Unavailablef creates a service unavailable error with a formatted message and the caller's trace.
+func Unavailablef(format string, args ...any) *CustomError {
+	return newTraced(WithMessage(fmt.Sprintf(format, args...)), WithCode(ErrorServiceUnavailable))
+}
+
+// Errorf creates an error with the given code and a message formatted like
+// fmt.Errorf. The operand of a %w verb becomes the cause, so the error still
+// unwraps to it; several %w operands are joined with errors.Join. The traces of
+// a wrapped CustomError are carried over, so Trace and Origin still lead to
+// where it was created.
+func Errorf(code ErrorCode, format string, args ...any) *CustomError {
+	formatted := fmt.Errorf(format, args...)
+	var cause error
+	switch u := formatted.(type) {
+	case interface{ Unwrap() error }:
+		cause = u.Unwrap()
+	case interface{ Unwrap() []error }:
+		cause = errors.Join(u.Unwrap()...)
+	}
+	e := newCustomError(WithMessage(formatted.Error()), WithCode(code), WithCause(cause))
+	var inner *CustomError
+	if errors.As(cause, &inner) {
+		// 감싼 CustomError의 추적을 이어받아 원래 발생 위치를 유지
+		e.Trace, e.PreviousTraces = inner.Trace, slices.Clone(inner.PreviousTraces)
+	} else if trace := foreignTrace(cause); trace != "" {
+		e.pushTrace(trace)
+	}
+	e.pushTrace(captureStackTrace(0, -1)) // wrapError 단계가 없으므로 한 프레임 덜 건너뜀
+	e.finish()
+	return e
+}
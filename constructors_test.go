@@ -0,0 +1,113 @@
+package exception
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestConstructors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *CustomError
+		code ErrorCode
+	}{
+		{"NotFound", NotFound("user 42"), ErrorNotFound},
+		{"NotFoundf", NotFoundf("user %d", 42), ErrorNotFound},
+		{"BadRequest", BadRequest("user 42"), ErrorBadRequest},
+		{"BadRequestf", BadRequestf("user %d", 42), ErrorBadRequest},
+		{"Unauthorized", Unauthorized("user 42"), ErrorUnAuthorized},
+		{"Unauthorizedf", Unauthorizedf("user %d", 42), ErrorUnAuthorized},
+		{"Forbidden", Forbidden("user 42"), ErrorForbidden},
+		{"Forbiddenf", Forbiddenf("user %d", 42), ErrorForbidden},
+		{"Conflict", Conflict("user 42"), ErrorConflict},
+		{"Conflictf", Conflictf("user %d", 42), ErrorConflict},
+		{"TooManyRequests", TooManyRequests("user 42"), ErrorTooManyRequests},
+		{"TooManyRequestsf", TooManyRequestsf("user %d", 42), ErrorTooManyRequests},
+		{"Internal", Internal("user 42"), ErrorInternalServer},
+		{"Internalf", Internalf("user %d", 42), ErrorInternalServer},
+		{"Unavailable", Unavailable("user 42"), ErrorServiceUnavailable},
+		{"Unavailablef", Unavailablef("user %d", 42), ErrorServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err.Error() != "user 42" || tt.err.Code() != tt.code {
+				t.Fatalf("%s = %q (%d), want %q (%d)", tt.name, tt.err.Error(), tt.err.Code(), "user 42", tt.code)
+			}
+			if got := tt.err.Origin().Function; got != "github.com/tae2089/exception.TestConstructors" {
+				t.Fatalf("origin = %q, want the constructor's caller", got)
+			}
+		})
+	}
+}
+
+func TestErrorf(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    *CustomError
+		msg    string
+		causes []error
+	}{
+		{"no cause", Errorf(ErrorConflict, "order %d exists", 7), "order 7 exists", nil},
+		{"one cause", Errorf(ErrorConflict, "create order: %w", io.EOF), "create order: EOF", []error{io.EOF}},
+		{"several causes", Errorf(ErrorConflict, "%w and %w", io.EOF, io.ErrClosedPipe), "EOF and io: read/write on closed pipe", []error{io.EOF, io.ErrClosedPipe}},
+		{"%v is not a cause", Errorf(ErrorConflict, "create order: %v", io.EOF), "create order: EOF", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err.Error() != tt.msg || tt.err.Code() != ErrorConflict {
+				t.Fatalf("Errorf = %q (%d)", tt.err.Error(), tt.err.Code())
+			}
+			if (tt.err.Unwrap() == nil) != (len(tt.causes) == 0) {
+				t.Fatalf("cause = %v", tt.err.Unwrap())
+			}
+			for _, cause := range tt.causes {
+				if !errors.Is(tt.err, cause) {
+					t.Fatalf("Errorf should unwrap to %v", cause)
+				}
+			}
+			if got := parseFrame(tt.err.Trace).Function; got != "github.com/tae2089/exception.TestErrorf" {
+				t.Fatalf("Trace = %s, want the Errorf call site", got)
+			}
+		})
+	}
+}
+
+func createInner() error {
+	return Wrap(io.EOF, WithCode(ErrorNotFound))
+}
+
+func TestErrorfCarriesInnerTraces(t *testing.T) {
+	inner := createInner()
+	innerTrace := inner.(*CustomError).Trace
+	err := Errorf(ErrorInternalServer, "load user: %w", inner)
+
+	if got := err.Origin().Function; got != "github.com/tae2089/exception.createInner" {
+		t.Fatalf("Origin = %s, want the inner error's origin", got)
+	}
+	if len(err.PreviousTraces) != 1 || err.PreviousTraces[0] != innerTrace {
+		t.Fatalf("PreviousTraces = %v, want [%s]", err.PreviousTraces, innerTrace)
+	}
+	if got := parseFrame(err.Trace).Function; got != "github.com/tae2089/exception.TestErrorfCarriesInnerTraces" {
+		t.Fatalf("Trace = %s, want the Errorf call site", got)
+	}
+	if !errors.Is(err, io.EOF) {
+		t.Fatal("Errorf should still unwrap to the inner cause")
+	}
+}
+
+func TestErrorfDoesNotShareInnerTraces(t *testing.T) {
+	inner := Wrap(Wrap(io.EOF)).(*CustomError)
+	err := Errorf(ErrorInternalServer, "outer: %w", inner)
+	Wrap(err)
+	if len(inner.PreviousTraces) != 1 {
+		t.Fatalf("wrapping the Errorf result changed the inner error: %v", inner.PreviousTraces)
+	}
+}
+
+func TestErrorfKeepsForeignTrace(t *testing.T) {
+	err := Errorf(ErrorInternalServer, "load: %w", newPCStackError())
+	if got := err.Origin().Function; got != "github.com/tae2089/exception.newPCStackError" {
+		t.Fatalf("Origin = %s, want the foreign stack", got)
+	}
+}
@@ -0,0 +1,39 @@
+package exception
+
+import "errors"
+
+// WithData returns err with a typed payload attached, such as the conflicting
+// resource of a conflict error, for handlers to extract with DataOf. A CustomError
+// is copied rather than modified; other errors are wrapped with the caller's trace.
+// It returns nil when err is nil.
+func WithData[T any](err error, payload T) error {
+	if isNil(err) {
+		return nil
+	}
+	if customErr, ok := err.(*CustomError); ok {
+		c := customErr.clone()
+		c.data = append(c.data, payload)
+		return c
+	}
+	return wrapError(err, withData(payload))
+}
+
+func withData(payload any) CustomErrorOption {
+	return func(e *CustomError) { e.data = append(e.data, payload) }
+}
+
+// DataOf returns the payload of type T most recently attached with WithData to a
+// CustomError in err's chain.
+func DataOf[T any](err error) (T, bool) {
+	var customErr *CustomError
+	for errors.As(err, &customErr) && customErr != nil {
+		for i := len(customErr.data) - 1; i >= 0; i-- {
+			if payload, ok := customErr.data[i].(T); ok {
+				return payload, true
+			}
+		}
+		err = customErr.Err
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,59 @@
+package exception
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+type conflictData struct {
+	ID string
+}
+
+func TestDataOf(t *testing.T) {
+	base := New("order exists", ErrorConflict)
+	tests := []struct {
+		name string
+		err  error
+		want conflictData
+		ok   bool
+	}{
+		{"nil", nil, conflictData{}, false},
+		{"no payload", base, conflictData{}, false},
+		{"custom error", WithData(base, conflictData{ID: "o-1"}), conflictData{ID: "o-1"}, true},
+		{"plain error", WithData(io.EOF, conflictData{ID: "o-2"}), conflictData{ID: "o-2"}, true},
+		{"latest wins", WithData(WithData(base, conflictData{ID: "o-1"}), conflictData{ID: "o-3"}), conflictData{ID: "o-3"}, true},
+		{"other type", WithData(base, "o-1"), conflictData{}, false},
+		{"fmt wrapped", fmt.Errorf("create: %w", WithData(base, conflictData{ID: "o-4"})), conflictData{ID: "o-4"}, true},
+		{"inner error", WrapMessage(WithData(io.EOF, conflictData{ID: "o-5"}), "create order"), conflictData{ID: "o-5"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DataOf[conflictData](tt.err)
+			if got != tt.want || ok != tt.ok {
+				t.Fatalf("DataOf = %+v, %v, want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestWithDataCopiesCustomError(t *testing.T) {
+	base := New("order exists", ErrorConflict)
+	withData := WithData(base, conflictData{ID: "o-1"})
+	if withData == error(base) {
+		t.Fatal("WithData should return a copy")
+	}
+	if _, ok := DataOf[conflictData](base); ok {
+		t.Fatal("WithData modified the original error")
+	}
+	if WithData[conflictData](nil, conflictData{}) != nil {
+		t.Fatal("WithData(nil) should return nil")
+	}
+	wrapped := WithData(io.EOF, 1).(*CustomError)
+	if wrapped.Trace == "" || wrapped.Unwrap() != io.EOF {
+		t.Fatalf("WithData on a plain error should wrap it with a trace: %+v", wrapped)
+	}
+	if got := parseFrame(wrapped.Trace).Function; got != "github.com/tae2089/exception.TestWithDataCopiesCustomError" {
+		t.Fatalf("trace frame = %q, want the caller of WithData", got)
+	}
+}
@@ -0,0 +1,83 @@
+package exception
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"runtime"
+	"time"
+)
+
+// dumpFormat identifies documents written by DumpTo.
+const dumpFormat = "exception-dump"
+
+type dumpDocument struct {
+	Format    string     `json:"format"`
+	Version   int        `json:"version"`
+	DumpedAt  time.Time  `json:"dumped_at"`
+	Hostname  string     `json:"hostname,omitempty"`
+	PID       int        `json:"pid"`
+	GoVersion string     `json:"go_version"`
+	Error     *wireError `json:"error"`
+}
+
+// DumpTo writes err and its whole cause chain to w as an indented, self-describing
+// JSON document for postmortems, e.g. from a crashing process. Unlike Encode, it
+// ignores the limits set with SetLimits, and it records when and where the dump
+// was taken. Load reads it back.
+func DumpTo(w io.Writer, err error) error {
+	hostname, _ := os.Hostname()
+	doc := dumpDocument{
+		Format:    dumpFormat,
+		Version:   SchemaVersion,
+		DumpedAt:  time.Now(),
+		Hostname:  hostname,
+		PID:       os.Getpid(),
+		GoVersion: runtime.Version(),
+		Error:     dumpableWire(toWire(err, maxEncodeDepth)),
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// Load reads an error written by DumpTo. Like Decode, the result is marked remote;
+// inspect it with PrintTrace or TracePoints.
+func Load(r io.Reader) (*CustomError, error) {
+	var doc dumpDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	if doc.Format != dumpFormat {
+		return nil, fmt.Errorf("exception: not an error dump (format %q)", doc.Format)
+	}
+	if doc.Error == nil {
+		return nil, fmt.Errorf("exception: error dump has no error")
+	}
+	return doc.Error.toCustomError(doc.Version), nil
+}
+
+// dumpableWire replaces the field values of w's chain that cannot be encoded as
+// JSON, such as functions, channels and cyclic values, with their fmt.Sprint
+// form, so a single field cannot make the whole dump fail. It returns w.
+func dumpableWire(w *wireError) *wireError {
+	for e := w; e != nil; e = e.Cause {
+		var fields map[string]any
+		for k, v := range e.Fields {
+			if _, err := json.Marshal(v); err == nil {
+				continue
+			}
+			if fields == nil {
+				// 원본 에러와 공유하는 맵이므로 복사해서 수정
+				fields = maps.Clone(e.Fields)
+			}
+			fields[k] = fmt.Sprint(v)
+		}
+		if fields != nil {
+			e.Fields = fields
+		}
+	}
+	return w
+}
@@ -0,0 +1,182 @@
+package exception
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDumpLoad(t *testing.T) {
+	remote, err := Decode([]byte(`{"version": 2, "id": "r-1", "code": 503, "message": "upstream down"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		limits Limits
+		err    func() error
+		check  func(t *testing.T, got *CustomError)
+	}{
+		{"chain", Limits{}, func() error {
+			return WrapMessage(fmt.Errorf("query: %w", Wrap(io.EOF, WithMessage("read user"), WithField("user", "u-1"), WithHint("retry"))), "load user")
+		}, func(t *testing.T, got *CustomError) {
+			var chain []string
+			for err := error(got); err != nil; err = errors.Unwrap(err) {
+				chain = append(chain, err.Error())
+			}
+			if want := []string{"load user", "query: read user", "read user", "EOF"}; !slices.Equal(chain, want) {
+				t.Fatalf("chain = %q, want %q", chain, want)
+			}
+			inner := got.Unwrap().(*CustomError).Unwrap().(*CustomError)
+			if inner.Fields()["user"] != "u-1" || inner.Hint() != "retry" || inner.Trace == "" {
+				t.Fatalf("inner error = %+v", inner)
+			}
+		}},
+		{"severity", Limits{}, func() error {
+			return New("boom", ErrorNotFound, WithSeverity(SeverityCritical))
+		}, func(t *testing.T, got *CustomError) {
+			if got.Severity() != SeverityCritical {
+				t.Fatalf("Severity = %s", got.Severity())
+			}
+		}},
+		{"derived severity", Limits{}, func() error {
+			return New("boom", ErrorNotFound)
+		}, func(t *testing.T, got *CustomError) {
+			if got.Severity() != SeverityWarning {
+				t.Fatalf("Severity = %s", got.Severity())
+			}
+		}},
+		{"remote", Limits{}, func() error {
+			return WrapMessage(remote, "call upstream")
+		}, func(t *testing.T, got *CustomError) {
+			cause, ok := got.Unwrap().(*CustomError)
+			if !got.IsRemote() || !ok || !cause.IsRemote() || cause.ID() != "r-1" {
+				t.Fatalf("loaded %v caused by %v", got, got.Unwrap())
+			}
+		}},
+		{"ops", Limits{}, func() error {
+			return Wrap(fmt.Errorf("query: %w", Wrap(io.EOF, WithMessage("read user"), WithOp("userRepo.Find"))), WithMessage("load user"), WithOp("userService.Get"))
+		}, func(t *testing.T, got *CustomError) {
+			if ops := Ops(got); !slices.Equal(ops, []string{"userService.Get", "userRepo.Find"}) {
+				t.Fatalf("Ops = %q", ops)
+			}
+		}},
+		{"tags", Limits{}, func() error {
+			return Wrap(fmt.Errorf("query: %w", Wrap(io.EOF, WithMessage("read user"), WithTags("db", "transient"))), WithMessage("load user"), WithTags("billing"))
+		}, func(t *testing.T, got *CustomError) {
+			if tags := Tags(got); !slices.Equal(tags, []string{"billing", "db", "transient"}) || !slices.Equal(got.Tags(), []string{"billing"}) {
+				t.Fatalf("Tags = %q, own tags %q", tags, got.Tags())
+			}
+		}},
+		{"truncated", Limits{MaxMessageLength: 20}, func() error {
+			return New(strings.Repeat("a", 40), ErrorInternalServer)
+		}, func(t *testing.T, got *CustomError) {
+			if !got.Truncated() || got.Error() != strings.Repeat("a", 6)+TruncatedMarker {
+				t.Fatalf("loaded %q, truncated %v", got.Error(), got.Truncated())
+			}
+		}},
+		{"unencodable fields", Limits{}, func() error {
+			cyclic := map[string]any{}
+			cyclic["self"] = &cyclic
+			inner := Wrap(io.EOF, WithField("callback", func() {}), WithField("user", "u-1"))
+			return Wrap(fmt.Errorf("query: %w", inner), WithMessage("load user"), WithField("cyclic", cyclic))
+		}, func(t *testing.T, got *CustomError) {
+			if _, ok := got.Fields()["cyclic"].(string); !ok {
+				t.Fatalf("fields = %v", got.Fields())
+			}
+			inner := got.Unwrap().(*CustomError).Unwrap().(*CustomError)
+			if v, ok := inner.Fields()["callback"].(string); !ok || !strings.HasPrefix(v, "0x") || inner.Fields()["user"] != "u-1" {
+				t.Fatalf("inner fields = %v", inner.Fields())
+			}
+		}},
+		{"serialized size ignored", Limits{MaxSerializedSize: 64}, func() error {
+			return WrapMessage(Wrap(io.EOF, WithField("user", strings.Repeat("u", 100))), "load user")
+		}, func(t *testing.T, got *CustomError) {
+			if got.Truncated() || got.Unwrap() == nil || got.Trace == "" {
+				t.Fatalf("DumpTo should write the whole chain: %+v", got)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setLimits(t, tt.limits)
+			want := tt.err()
+			var buf bytes.Buffer
+			if err := DumpTo(&buf, want); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Load(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			customErr := want.(*CustomError)
+			if got.ID() != customErr.ID() || got.Code() != customErr.Code() || !got.CreatedAt().Equal(customErr.CreatedAt()) {
+				t.Fatalf("loaded %s (%d) created %v, want %s (%d) created %v",
+					got.ID(), got.Code(), got.CreatedAt(), customErr.ID(), customErr.Code(), customErr.CreatedAt())
+			}
+			if got.PrintTrace() != remotePrinted(customErr) {
+				t.Fatalf("trace = %q, want %q", got.PrintTrace(), remotePrinted(customErr))
+			}
+			tt.check(t, got)
+		})
+	}
+}
+
+// remotePrinted returns the printed trace of e as it reads once decoded.
+func remotePrinted(e *CustomError) string {
+	c := *e
+	c.remote = true
+	return c.PrintTrace()
+}
+
+func TestDumpDocument(t *testing.T) {
+	var buf bytes.Buffer
+	if err := DumpTo(&buf, New("boom", ErrorInternalServer)); err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	hostname, _ := os.Hostname()
+	tests := []struct {
+		key  string
+		want any
+	}{
+		{"format", dumpFormat},
+		{"version", float64(SchemaVersion)},
+		{"pid", float64(os.Getpid())},
+		{"hostname", hostname},
+	}
+	for _, tt := range tests {
+		if doc[tt.key] != tt.want {
+			t.Errorf("%s = %v, want %v", tt.key, doc[tt.key], tt.want)
+		}
+	}
+	if !strings.Contains(buf.String(), "\n  \"error\": {") {
+		t.Fatalf("dump is not indented: %s", buf.String())
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"not JSON", "boom"},
+		{"encoded error", `{"version": 2, "code": 500, "message": "boom"}`},
+		{"other format", `{"format": "other", "error": {"message": "boom"}}`},
+		{"missing error", `{"format": "exception-dump", "version": 2}`},
+	}
+	for _, tt := range tests {
+		if got, err := Load(strings.NewReader(tt.data)); err == nil {
+			t.Errorf("%s: Load = %v, want an error", tt.name, got)
+		}
+	}
+}
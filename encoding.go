@@ -0,0 +1,218 @@
+package exception
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// SchemaVersion is the version of the serialization format written by Encode, and
+// by MarshalJSON once SetJSONCauseDepth is called. Version 1, which had no version field, stored traces as strings;
+// version 2 stores them as structured frames. Decode reads every version up to
+// SchemaVersion.
+const SchemaVersion = 2
+
+// maxEncodeDepth bounds the number of nested causes written by Encode.
+const maxEncodeDepth = 32
+
+// jsonCauseDepth is -1 until SetJSONCauseDepth is called, for the legacy format.
+var jsonCauseDepth = -1
+
+// SetJSONCauseDepth makes json.Marshal write a CustomError in the format of
+// Encode, with depth nested causes under "cause", each with its own message, code
+// and trace; 0 writes only the error itself. Until it is called, json.Marshal
+// writes the legacy document with the "message", "trace" and "previous_traces"
+// fields. Encode always writes the full chain. It should be called during
+// initialization.
+func SetJSONCauseDepth(depth int) {
+	jsonCauseDepth = min(max(depth, 0), maxEncodeDepth-1)
+}
+
+// wireError is the JSON representation of an error chain exchanged between
+// services. Only the outermost error carries the version.
+type wireError struct {
+	Version       int            `json:"version,omitempty"`
+	ID            string         `json:"id,omitempty"`
+	CreatedAt     time.Time      `json:"created_at,omitzero"`
+	Code          ErrorCode      `json:"code,omitempty"`
+	Domain        string         `json:"domain,omitempty"`
+	Message       string         `json:"message"`
+	PublicMessage string         `json:"public_message,omitempty"`
+	Hint          string         `json:"hint,omitempty"`
+	Retryable     *bool          `json:"retryable,omitempty"`
+	RetryAfter    time.Duration  `json:"retry_after,omitempty"`
+	Severity      string         `json:"severity,omitempty"`
+	Fields        map[string]any `json:"fields,omitempty"`
+	Ops           []string       `json:"ops,omitempty"`
+	Tags          []string       `json:"tags,omitempty"`
+	Traces        []TracePoint   `json:"traces,omitempty"`
+	Cause         *wireError     `json:"cause,omitempty"`
+	Truncated     bool           `json:"truncated,omitempty"`
+
+	// Trace and PreviousTraces are only read from version 1 documents.
+	Trace          string   `json:"trace,omitempty"`
+	PreviousTraces []string `json:"previous_traces,omitempty"`
+}
+
+func toWireTraces(e *CustomError) []TracePoint {
+	points := e.TracePoints()
+	for i := range points {
+		if points[i].Count == 1 {
+			points[i].Count = 0
+		}
+	}
+	return points
+}
+
+func fromWireTraces(traces []TracePoint) (string, []string) {
+	if len(traces) == 0 {
+		return "", nil
+	}
+	entries := make([]string, len(traces))
+	for i, trace := range traces {
+		entries[i] = formatTrace(trace.Frames, trace.Count)
+	}
+	return entries[0], entries[1:]
+}
+
+// toVersionedWire converts err to the current schema version.
+func toVersionedWire(err error, depth int) *wireError {
+	w := toWire(err, depth)
+	if w != nil {
+		w.Version = SchemaVersion
+	}
+	return w
+}
+
+func toWire(err error, depth int) *wireError {
+	if isNil(err) || depth <= 0 {
+		return nil
+	}
+	customErr, ok := err.(*CustomError)
+	if !ok {
+		return &wireError{Message: err.Error(), Cause: toWire(errors.Unwrap(err), depth-1)}
+	}
+	return &wireError{
+		ID:            customErr.id,
+		CreatedAt:     customErr.created,
+		Code:          customErr.code,
+		Domain:        customErr.domain,
+		Message:       customErr.message(),
+		PublicMessage: customErr.publicMessage,
+		Hint:          customErr.hint,
+		Retryable:     customErr.retryable,
+		RetryAfter:    customErr.retryAfter,
+		Severity:      wireSeverity(customErr.severity),
+		Fields:        customErr.fields,
+		Ops:           customErr.ops,
+		Tags:          customErr.tags,
+		Traces:        toWireTraces(customErr),
+		Cause:         toWire(customErr.Err, depth-1),
+		Truncated:     customErr.Truncated(),
+	}
+}
+
+func (w *wireError) toCustomError(version int) *CustomError {
+	if w == nil {
+		return nil
+	}
+	e := &CustomError{}
+	if sentinel, ok := SentinelFor(w.Code); ok {
+		e = sentinel.clone()
+	}
+	e.id = w.ID
+	e.created = w.CreatedAt
+	e.code = w.Code
+	if w.Domain != "" {
+		e.domain = w.Domain
+	}
+	if w.Message != "" {
+		e.Message = w.Message
+	}
+	if w.PublicMessage != "" {
+		e.publicMessage = w.PublicMessage
+	}
+	if w.Hint != "" {
+		e.hint = w.Hint
+	}
+	if w.Retryable != nil {
+		e.retryable = w.Retryable
+	}
+	if w.RetryAfter != 0 {
+		e.retryAfter = w.RetryAfter
+	}
+	if severity, ok := parseSeverity(w.Severity); ok {
+		e.severity = severity
+	}
+	for k, v := range w.Fields {
+		e.setField(k, v)
+	}
+	if len(w.Ops) > 0 {
+		e.ops = w.Ops
+	}
+	for _, tag := range w.Tags {
+		e.addTag(tag)
+	}
+	if version < 2 {
+		e.Trace, e.PreviousTraces = w.Trace, w.PreviousTraces
+	} else {
+		e.Trace, e.PreviousTraces = fromWireTraces(w.Traces)
+	}
+	e.truncated = w.Truncated
+	e.remote = true
+	if cause := w.Cause.toCustomError(version); cause != nil {
+		e.Err = cause
+	}
+	return e
+}
+
+// Encode serializes err and its cause chain so that another service can rebuild
+// it with Decode.
+func Encode(err error) ([]byte, error) {
+	return marshalLimited(toVersionedWire(err, maxEncodeDepth))
+}
+
+// Decode rebuilds a CustomError serialized by Encode in another service, including
+// its nested causes. Documents of older schema versions are accepted, and unknown
+// fields written by newer versions are ignored. The rebuilt errors are marked
+// remote and start from the registered sentinel for their code, if any, so code
+// predicates and errors.Is keep working across service hops.
+func Decode(data []byte) (*CustomError, error) {
+	var w wireError
+	if err := json.Unmarshal(data, &w); err != nil {
+		return nil, err
+	}
+	return w.toCustomError(w.Version), nil
+}
+
+// legacyJSON is the document json.Marshal writes until SetJSONCauseDepth is
+// called: the exported fields CustomError was serialized with before it
+// implemented json.Marshaler.
+type legacyJSON struct {
+	Message        string   `json:"message"`
+	Trace          string   `json:"trace"`
+	PreviousTraces []string `json:"previous_traces"`
+}
+
+// MarshalJSON writes the legacy document or, once SetJSONCauseDepth is called, the
+// error and as many nested causes as set with it in the format used by Encode.
+func (e *CustomError) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+	if jsonCauseDepth < 0 {
+		return json.Marshal(legacyJSON{Message: e.message(), Trace: e.Trace, PreviousTraces: e.PreviousTraces})
+	}
+	return marshalLimited(toVersionedWire(e, jsonCauseDepth+1))
+}
+
+// UnmarshalJSON reads an error written by MarshalJSON or Encode. Like Decode, the
+// result is marked remote.
+func (e *CustomError) UnmarshalJSON(data []byte) error {
+	var w wireError
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	*e = *w.toCustomError(w.Version)
+	return nil
+}
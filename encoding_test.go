@@ -0,0 +1,256 @@
+package exception
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	orig := Wrap(fmt.Errorf("query: %w", io.EOF), WithCode(ErrorDataNotFound), WithMessage("user missing"),
+		WithPublicMessage("not found"), WithHint("check the id"), WithField("id", "u-7"), WithDomain("users"), WithRetryable(true), WithRetryAfter(time.Second)).(*CustomError)
+	orig = WrapMessage(orig, "load user").(*CustomError)
+
+	data, err := Encode(orig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		got, want any
+	}{
+		{"id", got.ID(), orig.ID()},
+		{"code", got.Code(), orig.Code()},
+		{"domain", got.Domain(), "users"},
+		{"message", got.Message, orig.Message},
+		{"public message", got.PublicMessage(), "not found"},
+		{"hint", got.Hint(), "check the id"},
+		{"retryable", IsRetryable(got), true},
+		{"retry after", got.RetryAfter(), time.Second},
+		{"field", fmt.Sprint(got.Fields()), fmt.Sprint(orig.Fields())},
+		{"trace", got.Trace, orig.Trace},
+		{"previous traces", fmt.Sprint(got.PreviousTraces), fmt.Sprint(orig.PreviousTraces)},
+		{"remote", got.IsRemote(), true},
+		{"cause message", got.Err.Error(), "query: EOF"},
+		{"cause of cause", errors.Unwrap(got.Err).Error(), "EOF"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Fatalf("%s = %#v, want %#v", tt.name, tt.got, tt.want)
+		}
+	}
+	if !strings.HasPrefix(got.PrintTrace(), "[remote] ") {
+		t.Fatalf("PrintTrace should mark remote traces: %q", got.PrintTrace())
+	}
+}
+
+func TestEncodeNilAndDepth(t *testing.T) {
+	if data, err := Encode(nil); err != nil || string(data) != "null" {
+		t.Fatalf("Encode(nil) = %s, %v", data, err)
+	}
+	var err error = io.EOF
+	for range maxEncodeDepth + 5 {
+		err = fmt.Errorf("layer: %w", err)
+	}
+	data, _ := Encode(err)
+	got, _ := Decode(data)
+	depth := 0
+	for e := error(got); e != nil; e = errors.Unwrap(e) {
+		depth++
+	}
+	if depth != maxEncodeDepth {
+		t.Fatalf("decoded %d errors, want %d", depth, maxEncodeDepth)
+	}
+	if _, err := Decode([]byte("{")); err == nil {
+		t.Fatal("Decode should fail on invalid JSON")
+	}
+}
+
+func TestDecodeStartsFromSentinel(t *testing.T) {
+	resetSentinels(t)
+	sentinel := RegisterSentinel(ErrorUserNotFound, New("user missing", 0).WithPublic("no such user"))
+	data, _ := Encode(New("", ErrorUserNotFound))
+	got, _ := Decode(data)
+	if got == sentinel || got.frozen || got.PublicMessage() != "no such user" {
+		t.Fatalf("Decode should start from an unfrozen copy of the sentinel: %+v", got)
+	}
+	if got.Message != "user missing" {
+		t.Fatalf("an empty message should keep the sentinel's: %q", got.Message)
+	}
+}
+
+func TestWrapRemoteKeepsItAsCause(t *testing.T) {
+	data, _ := Encode(New("upstream failed", ErrorDataNotFound))
+	remote, _ := Decode(data)
+	wrapped := WrapMessage(remote, "call upstream").(*CustomError)
+	if wrapped == remote || wrapped.IsRemote() || wrapped.Err != error(remote) {
+		t.Fatalf("Wrap should keep the remote error as cause: %+v", wrapped)
+	}
+	if remote.Message != "upstream failed" || remote.Code() != ErrorDataNotFound {
+		t.Fatalf("the remote error changed: %+v", remote)
+	}
+}
+
+func setJSONCauseDepth(t *testing.T, depth int) {
+	t.Helper()
+	prev := jsonCauseDepth
+	SetJSONCauseDepth(depth)
+	t.Cleanup(func() { jsonCauseDepth = prev })
+}
+
+func TestJSONCauseDepth(t *testing.T) {
+	chain := WrapMessage(WrapMessage(fmt.Errorf("query: %w", io.EOF), "load user"), "handle request")
+	tests := []struct {
+		name  string
+		depth int
+		want  int
+	}{
+		{"error only", 0, 0},
+		{"negative", -1, 0},
+		{"one cause", 1, 1},
+		{"whole chain", 5, 2},
+		{"beyond the encode limit", maxEncodeDepth + 5, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setJSONCauseDepth(t, tt.depth)
+			data, err := json.Marshal(chain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got CustomError
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Error() != "handle request" || !got.IsRemote() {
+				t.Fatalf("unmarshaled %+v", got)
+			}
+			causes := 0
+			for e := got.Unwrap(); e != nil; e = errors.Unwrap(e) {
+				causes++
+			}
+			if causes != tt.want {
+				t.Fatalf("unmarshaled %d causes, want %d: %s", causes, tt.want, data)
+			}
+		})
+	}
+}
+
+func TestMarshalJSONLegacy(t *testing.T) {
+	prev := jsonCauseDepth
+	jsonCauseDepth = -1
+	t.Cleanup(func() { jsonCauseDepth = prev })
+	err := WrapMessage(WrapMessage(io.EOF, "load user"), "handle request").(*CustomError)
+	data, marshalErr := json.Marshal(err)
+	if marshalErr != nil {
+		t.Fatal(marshalErr)
+	}
+	// SetJSONCauseDepth를 호출하기 전에는 기존 필드만 기록
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc) != 3 || doc["message"] != "handle request" || doc["trace"] != err.Trace || len(doc["previous_traces"].([]any)) != len(err.PreviousTraces) {
+		t.Fatalf("document = %s", data)
+	}
+	var got CustomError
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Error() != "handle request" || got.Trace != err.Trace || !slices.Equal(got.PreviousTraces, err.PreviousTraces) {
+		t.Fatalf("unmarshaled %+v", got)
+	}
+}
+
+func TestMarshalJSONNil(t *testing.T) {
+	var customErr *CustomError
+	data, err := json.Marshal(customErr)
+	if err != nil || string(data) != "null" {
+		t.Fatalf("json.Marshal(nil) = %s, %v", data, err)
+	}
+	if err := json.Unmarshal([]byte("{"), &CustomError{}); err == nil {
+		t.Fatal("UnmarshalJSON should fail on invalid JSON")
+	}
+}
+
+func TestEncodeWritesFrames(t *testing.T) {
+	setTraceDedup(t, DedupOnWrap)
+	var err error = io.EOF
+	for range 3 {
+		err = WrapMessage(err, "read")
+	}
+	data, _ := Encode(err)
+	var w wireError
+	if err := json.Unmarshal(data, &w); err != nil {
+		t.Fatal(err)
+	}
+	if w.Version != SchemaVersion || w.Trace != "" || len(w.PreviousTraces) != 0 {
+		t.Fatalf("document = %s", data)
+	}
+	if len(w.Traces) != 1 || w.Traces[0].Count != 3 || len(w.Traces[0].Frames) != 1 {
+		t.Fatalf("traces = %+v", w.Traces)
+	}
+	if got := w.Traces[0].Frames[0].Function; got != "github.com/tae2089/exception.TestEncodeWritesFrames" {
+		t.Fatalf("frame function = %q", got)
+	}
+	if w.Cause == nil || w.Cause.Version != 0 {
+		t.Fatalf("only the outermost error should carry the version: %s", data)
+	}
+}
+
+func TestDecodeVersions(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		origin Frame
+		traces int
+	}{
+		{
+			name:   "version 1",
+			data:   `{"id": "abc", "code": 404, "message": "user missing", "trace": "/app/main.go:10 main.load", "previous_traces": ["/app/main.go:20 main.find"], "cause": {"message": "EOF"}}`,
+			origin: Frame{File: "/app/main.go", Line: 20, Function: "main.find"},
+			traces: 1,
+		},
+		{
+			name:   "version 2",
+			data:   `{"version": 2, "id": "abc", "code": 404, "message": "user missing", "traces": [{"frames": [{"file": "/app/main.go", "line": 10, "function": "main.load"}]}, {"frames": [{"file": "/app/main.go", "line": 20, "function": "main.find"}], "count": 2}], "cause": {"message": "EOF"}}`,
+			origin: Frame{File: "/app/main.go", Line: 20, Function: "main.find"},
+			traces: 1,
+		},
+		{
+			name:   "unknown fields of a newer version",
+			data:   `{"version": 3, "id": "abc", "code": 404, "message": "user missing", "future": {"a": 1}, "cause": {"message": "EOF"}}`,
+			traces: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := Decode([]byte(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decoded.ID() != "abc" || decoded.Code() != ErrorNotFound || decoded.Error() != "user missing" {
+				t.Fatalf("decoded = %s %d %q", decoded.ID(), decoded.Code(), decoded.Error())
+			}
+			if got := decoded.Origin(); got != tt.origin {
+				t.Fatalf("Origin = %+v, want %+v", got, tt.origin)
+			}
+			if len(decoded.PreviousTraces) != tt.traces && tt.traces >= 0 {
+				t.Fatalf("previous traces = %v", decoded.PreviousTraces)
+			}
+			if decoded.Unwrap() == nil || decoded.Unwrap().Error() != "EOF" {
+				t.Fatalf("cause = %v", decoded.Unwrap())
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package exception
 
 import (
+	"errors"
 	"fmt"
-	"runtime"
+	"math/rand/v2"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 type ErrorCode int
@@ -20,6 +23,17 @@ const (
 	ErrorUserExists         ErrorCode = 409
 	ErrorInternalDB         ErrorCode = 500
 	ErrorInvalidRequest     ErrorCode = 400
+	ErrorBadRequest         ErrorCode = 400
+	ErrorForbidden          ErrorCode = 403
+	ErrorNotFound           ErrorCode = 404
+	ErrorConflict           ErrorCode = 409
+	ErrorTooManyRequests    ErrorCode = 429
+	// ErrorClientClosedRequest marks requests the client abandoned before the
+	// response was written, following nginx's 499.
+	ErrorClientClosedRequest ErrorCode = 499
+	ErrorBadGateway          ErrorCode = 502
+	ErrorServiceUnavailable  ErrorCode = 503
+	ErrorGatewayTimeout      ErrorCode = 504
 )
 
 type CustomError struct {
@@ -28,6 +42,35 @@ type CustomError struct {
 	Trace          string   `json:"trace"`
 	PreviousTraces []string `json:"previous_traces"`
 	Err            error    `json:"-"`
+
+	id            string
+	created       time.Time
+	domain        string
+	fields        map[string]any
+	publicMessage string
+	lazy          *lazyMessage
+	data          []any
+	ops           []string
+	tags          []string
+	hint          string
+	retryable     *bool
+	retryAfter    time.Duration
+	severity      Severity
+
+	// remote errors were decoded from another service; see Decode.
+	remote bool
+	// truncated errors had their message or fields cut; see SetLimits.
+	truncated bool
+	// frozen errors are shared and copied before being modified; see RegisterSentinel.
+	frozen bool
+	// occurrences counts how often an interned error was returned; see Interner.
+	occurrences *atomic.Int64
+
+	// stackDepth and callerSkip are consumed by the next capture; see WithStackDepth.
+	stackDepth int
+	callerSkip int
+	noTrace    bool
+	callerOp   bool
 }
 
 type CustomErrorOption func(*CustomError)
@@ -48,42 +91,336 @@ func WithCause(err error) CustomErrorOption {
 	return func(e *CustomError) { e.Err = err }
 }
 
+// WithField attaches a key-value pair to the error.
+func WithField(key string, value any) CustomErrorOption {
+	return func(e *CustomError) { e.setField(key, value) }
+}
+
+// WithFields attaches all key-value pairs of fields to the error.
+func WithFields(fields map[string]any) CustomErrorOption {
+	return func(e *CustomError) {
+		for k, v := range fields {
+			e.setField(k, v)
+		}
+	}
+}
+
+// WithPublicMessage sets the message that is safe to show to end users.
+func WithPublicMessage(msg string) CustomErrorOption {
+	return func(e *CustomError) { e.publicMessage = msg }
+}
+
+// WithID overrides the generated error ID, e.g. to keep the ID received from another service.
+func WithID(id string) CustomErrorOption {
+	return func(e *CustomError) { e.id = id }
+}
+
+// WithDomain sets the domain, such as the service or subsystem, that the error belongs to.
+func WithDomain(domain string) CustomErrorOption {
+	return func(e *CustomError) { e.domain = domain }
+}
+
+// WithRemote marks the error as received from another service.
+func WithRemote() CustomErrorOption {
+	return func(e *CustomError) { e.remote = true }
+}
+
+// WithHint attaches a remediation hint, such as what the caller should check.
+func WithHint(hint string) CustomErrorOption {
+	return func(e *CustomError) { e.hint = hint }
+}
+
+// WithRetryable marks the error as retryable or not, overriding the default derived from its code.
+func WithRetryable(retryable bool) CustomErrorOption {
+	return func(e *CustomError) { e.retryable = &retryable }
+}
+
+// WithRetryAfter sets how long callers should wait before retrying, e.g. from a
+// Retry-After header. It makes the error retryable unless WithRetryable(false) is set.
+func WithRetryAfter(d time.Duration) CustomErrorOption {
+	return func(e *CustomError) { e.retryAfter = d }
+}
+
+// WithSeverity overrides the severity derived from the error's code.
+func WithSeverity(severity Severity) CustomErrorOption {
+	return func(e *CustomError) { e.severity = severity }
+}
+
+// WithStackDepth overrides the number of frames captured by this wrap call.
+func WithStackDepth(depth int) CustomErrorOption {
+	return func(e *CustomError) { e.stackDepth = depth }
+}
+
+// WithNoTrace skips trace capture for this call, for expected high-frequency errors
+// where the location is not worth the cost.
+func WithNoTrace() CustomErrorOption {
+	return func(e *CustomError) { e.noTrace = true }
+}
+
+// WithCallerSkip skips additional frames when capturing the trace, so helpers that
+// wrap errors on behalf of their caller record the caller's location.
+func WithCallerSkip(skip int) CustomErrorOption {
+	return func(e *CustomError) { e.callerSkip = skip }
+}
+
 func (e *CustomError) Error() string {
-	if e.Message == "" {
-		return "unknown error"
+	if e == nil {
+		return "<nil>"
+	}
+	if msg := e.message(); msg != "" {
+		return msg
 	}
-	return e.Message
+	return DefaultMessage(e.code)
 }
 
 func (e *CustomError) Cause() error {
+	if e == nil {
+		return nil
+	}
 	return e.Err
 }
 
 func (e *CustomError) Code() ErrorCode {
+	if e == nil {
+		return 0
+	}
 	return e.code
 }
 
 func (e *CustomError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
 	return e.Err
 }
 
+// Is reports whether target is a CustomError with the same code. Other targets
+// such as sql.ErrNoRows are matched by errors.Is itself as it walks Unwrap.
 func (e *CustomError) Is(target error) bool {
-	if t, ok := target.(*CustomError); ok {
+	if e == nil {
+		return false
+	}
+	if t, ok := target.(*CustomError); ok && t != nil {
 		return e.code == t.code
 	}
 	return false
 }
 
 func (e *CustomError) PrintTrace() string {
-	if e.Trace == "" {
+	if e == nil || e.Trace == "" {
 		return ""
 	}
 	allTraces := append([]string{e.Trace}, e.PreviousTraces...)
+	if traceDedupMode == DedupOnFormat {
+		allTraces = dedupTraces(allTraces)
+	}
+	if e.remote {
+		for i, trace := range allTraces {
+			allTraces[i] = "[remote] " + trace
+		}
+	}
 	return strings.Join(allTraces, "\n")
 }
 
+// Fields returns a copy of the key-value pairs attached to the error.
+func (e *CustomError) Fields() map[string]any {
+	if e == nil || len(e.fields) == 0 {
+		return nil
+	}
+	fields := make(map[string]any, len(e.fields))
+	for k, v := range e.fields {
+		fields[k] = v
+	}
+	return fields
+}
+
+// Field returns the value attached to the error under key.
+func (e *CustomError) Field(key string) (any, bool) {
+	if e == nil {
+		return nil, false
+	}
+	v, ok := e.fields[key]
+	return v, ok
+}
+
+// PublicMessage returns the message that is safe to show to end users, falling
+// back to the public message registered in the catalog for the error's code.
+func (e *CustomError) PublicMessage() string {
+	if e == nil {
+		return ""
+	}
+	if e.publicMessage == "" {
+		if info, ok := LookupCode(e.code); ok {
+			return info.PublicMessage
+		}
+	}
+	return e.publicMessage
+}
+
+// ID returns the identifier generated when the error was created.
+func (e *CustomError) ID() string {
+	if e == nil {
+		return ""
+	}
+	return e.id
+}
+
+// CreatedAt returns when the error was created, or when a decoded error was
+// created in the service that sent it.
+func (e *CustomError) CreatedAt() time.Time {
+	if e == nil {
+		return time.Time{}
+	}
+	return e.created
+}
+
+// Domain returns the domain the error belongs to.
+func (e *CustomError) Domain() string {
+	if e == nil {
+		return ""
+	}
+	return e.domain
+}
+
+// IsRemote reports whether the error was decoded from another service.
+func (e *CustomError) IsRemote() bool {
+	return e != nil && e.remote
+}
+
+// RetryAfter returns how long callers should wait before retrying the error.
+func (e *CustomError) RetryAfter() time.Duration {
+	if e == nil {
+		return 0
+	}
+	return e.retryAfter
+}
+
+// Hint returns the remediation hint attached to the error.
+func (e *CustomError) Hint() string {
+	if e == nil {
+		return ""
+	}
+	return e.hint
+}
+
+func (e *CustomError) setField(key string, value any) {
+	if e.fields == nil {
+		e.fields = make(map[string]any)
+	}
+	e.fields[key] = value
+}
+
+// clone returns a copy of e that shares no mutable state with it, except the
+// occurrence counter of an interned error, so copies keep reporting it.
+func (e *CustomError) clone() *CustomError {
+	c := *e
+	c.frozen = false
+	c.PreviousTraces = append([]string(nil), e.PreviousTraces...)
+	c.fields = e.Fields()
+	c.data = append([]any(nil), e.data...)
+	c.ops = append([]string(nil), e.ops...)
+	c.tags = append([]string(nil), e.tags...)
+	return &c
+}
+
+// WithCodeC returns a copy of the error with the given code.
+func (e *CustomError) WithCodeC(code ErrorCode) *CustomError {
+	if e == nil {
+		return nil
+	}
+	c := e.clone()
+	c.code = code
+	return c
+}
+
+// WithFieldC returns a copy of the error with the key-value pair attached.
+func (e *CustomError) WithFieldC(key string, value any) *CustomError {
+	if e == nil {
+		return nil
+	}
+	c := e.clone()
+	c.setField(key, value)
+	return c
+}
+
+// WithPublic returns a copy of the error with the given public message.
+func (e *CustomError) WithPublic(msg string) *CustomError {
+	if e == nil {
+		return nil
+	}
+	c := e.clone()
+	c.publicMessage = msg
+	return c
+}
+
+// TracePoints returns the capture points of the error, latest first.
+func (e *CustomError) TracePoints() []TracePoint {
+	if e == nil || e.Trace == "" && len(e.PreviousTraces) == 0 {
+		return nil
+	}
+	points := make([]TracePoint, 0, 1+len(e.PreviousTraces))
+	for _, trace := range append([]string{e.Trace}, e.PreviousTraces...) {
+		frames, count := traceFrames(trace)
+		points = append(points, TracePoint{Frames: frames, Count: count})
+	}
+	return points
+}
+
+// LatestTrace returns the most recent capture point of the error.
+func (e *CustomError) LatestTrace() string {
+	if e == nil {
+		return ""
+	}
+	trace, _ := splitTraceCount(e.Trace)
+	return trace
+}
+
+// OriginTrace returns the oldest capture point of the error, where it was first wrapped.
+func (e *CustomError) OriginTrace() string {
+	if e == nil {
+		return ""
+	}
+	for i := len(e.PreviousTraces) - 1; i >= 0; i-- {
+		if e.PreviousTraces[i] != "" {
+			trace, _ := splitTraceCount(e.PreviousTraces[i])
+			return trace
+		}
+	}
+	return e.LatestTrace()
+}
+
+// Origin returns the oldest capture point of the error as a Frame.
+func (e *CustomError) Origin() Frame {
+	return parseFrame(e.OriginTrace())
+}
+
+// callOptions are the per-call settings of WithStackDepth, WithCallerSkip,
+// WithNoTrace and WithCallerOp.
+type callOptions struct {
+	depth    int
+	skip     int
+	noTrace  bool
+	callerOp bool
+}
+
+// takeCallOptions returns the per-call settings and clears them, so they never
+// carry over to a later Wrap of the same error.
+func (e *CustomError) takeCallOptions() callOptions {
+	o := callOptions{depth: e.stackDepth, skip: e.callerSkip, noTrace: e.noTrace, callerOp: e.callerOp}
+	e.stackDepth, e.callerSkip, e.noTrace, e.callerOp = 0, 0, false, false
+	return o
+}
+
+// finish applies the limits to a newly created error and audits it. Per-call
+// settings passed to a constructor that does not capture a trace are dropped.
+func (e *CustomError) finish() {
+	e.takeCallOptions()
+	e.applyLimits()
+	e.audit()
+}
+
 func newCustomError(opts ...CustomErrorOption) *CustomError {
-	e := &CustomError{}
+	e := &CustomError{id: newID(), created: time.Now()}
 	for _, opt := range opts {
 		opt(e)
 	}
@@ -91,52 +428,143 @@ func newCustomError(opts ...CustomErrorOption) *CustomError {
 }
 
 // New creates a new CustomError with the given message and code
-func New(msg string, code ErrorCode) *CustomError {
-	return newCustomError(WithMessage(msg), WithCode(code))
+func New(msg string, code ErrorCode, opts ...CustomErrorOption) *CustomError {
+	e := newCustomError(append([]CustomErrorOption{WithMessage(msg), WithCode(code)}, opts...)...)
+	e.finish()
+	return e
 }
 
 func wrapError(err error, opts ...CustomErrorOption) error {
-	trace := captureStackTrace()
-	var previousTraces []string
-	if customErr, ok := err.(*CustomError); ok {
-		previousTraces = append([]string{customErr.Trace}, customErr.PreviousTraces...)
-		// 기존 에러 업데이트
-		customErr.Trace = trace
-		customErr.PreviousTraces = previousTraces
-		for _, opt := range opts {
-			opt(customErr)
+	if isNil(err) {
+		return nil
+	}
+	// 기존 에러는 그대로 업데이트
+	customErr, ok := err.(*CustomError)
+	created := true
+	if !ok {
+		customErr = newCustomError(WithCause(err))
+		// 외부 라이브러리가 기록한 스택은 가장 오래된 추적으로 보존
+		if trace := foreignTrace(err); trace != "" {
+			customErr.pushTrace(trace)
+		}
+	} else if customErr.remote {
+		// 원격 에러는 그대로 두고 로컬 에러의 원인으로 연결
+		customErr = newCustomError(WithCause(err), WithCode(customErr.code))
+	} else if customErr.frozen {
+		customErr = customErr.clone()
+		customErr.id, customErr.created = newID(), time.Now()
+	} else {
+		created = false
+	}
+	code, domain := customErr.code, customErr.domain
+	for _, opt := range opts {
+		opt(customErr)
+	}
+	call := customErr.takeCallOptions()
+	if !call.noTrace || call.callerOp {
+		trace := captureStackTrace(call.depth, call.skip)
+		if call.callerOp {
+			customErr.ops = append(customErr.ops, opName(parseFrame(trace).Function))
+		}
+		if !call.noTrace {
+			customErr.pushTrace(trace)
 		}
-		return customErr
 	}
-	allOpts := append([]CustomErrorOption{WithTrace(trace), WithPreviousTraces(previousTraces), WithCause(err)}, opts...)
-	return newCustomError(allOpts...)
+	customErr.applyLimits()
+	// 새로 만든 에러이거나 감사 대상 코드·도메인으로 바뀐 경우 감사
+	if created || customErr.code != code || customErr.domain != domain {
+		customErr.audit()
+	}
+	return customErr
+}
+
+// pushTrace records trace as the latest capture point, moving the current one
+// into PreviousTraces.
+func (e *CustomError) pushTrace(trace string) {
+	if e.Trace == "" && len(e.PreviousTraces) == 0 {
+		e.Trace = trace
+		return
+	}
+	if base, n := splitTraceCount(e.Trace); traceDedupMode == DedupOnWrap && base == trace {
+		// 같은 위치에서 반복된 경우 카운터만 증가
+		e.Trace = joinTraceCount(base, n+1)
+		return
+	}
+	e.PreviousTraces = append([]string{e.Trace}, e.PreviousTraces...)
+	e.Trace = trace
 }
 
+// Wrap wraps err with the given options, capturing the caller's trace.
+// It returns nil when err is nil.
+func Wrap(err error, opts ...CustomErrorOption) error {
+	return wrapError(err, opts...)
+}
+
+// WrapTrace wraps err, capturing the caller's trace. A CustomError keeps its
+// message, and one created without a message keeps rendering the default message
+// of its code; see DefaultMessage. Other errors get the ErrorInternalServer code
+// and its default message followed by theirs, e.g. "internal server error: EOF".
 func WrapTrace(err error) error {
-	return wrapError(err, WithMessage("An error occurred"))
+	if _, ok := err.(*CustomError); ok || err == nil {
+		return wrapError(err)
+	}
+	return wrapError(err, WithCode(ErrorInternalServer), WithMessage(DefaultMessage(ErrorInternalServer)+": "+err.Error()))
+}
+
+// WithStack attaches the caller's trace to err while keeping its message. Errors
+// that are not CustomErrors get the ErrorInternalServer code.
+func WithStack(err error) error {
+	if _, ok := err.(*CustomError); ok {
+		return wrapError(err, withCauseMessage())
+	}
+	return wrapError(err, withCauseMessage(), WithCode(ErrorInternalServer))
+}
+
+// withCauseMessage uses the message of the wrapped cause when the error has none.
+func withCauseMessage() CustomErrorOption {
+	return func(e *CustomError) {
+		if e.message() == "" && e.Err != nil {
+			e.Message = e.Err.Error()
+		}
+	}
 }
 
 func WrapMessageWithCode(err error, errCode ErrorCode, msg string) error {
 	return wrapError(err, WithMessage(msg), WithCode(errCode))
 }
 
+// WrapMessagefWithCode wraps err with the given code and a message formatted
+// according to format.
+func WrapMessagefWithCode(err error, errCode ErrorCode, format string, args ...any) error {
+	return wrapError(err, WithMessage(fmt.Sprintf(format, args...)), WithCode(errCode))
+}
+
 func WrapMessage(err error, msg string) error {
 	return wrapError(err, WithMessage(msg), WithCode(ErrorInternalServer))
 }
 
-func captureStackTrace() string {
-	var pcs [1]uintptr
-	n := runtime.Callers(4, pcs[:]) // 3을 사용하여 호출자의 호출자에서 시작
-	if n == 0 {
-		return "unknown"
+// WrapIf wraps err like WrapMessage, returning nil when err is nil.
+func WrapIf(err error, msg string) error {
+	if isNil(err) {
+		return nil
 	}
-	frame, _ := runtime.CallersFrames(pcs[:n]).Next()
-	return fmt.Sprintf("%s:%d %s", frame.File, frame.Line, frame.Function)
+	return wrapError(err, WithMessage(msg), WithCode(ErrorInternalServer))
+}
+
+// WrapIfWithCode wraps err like WrapMessageWithCode, returning nil when err is nil.
+func WrapIfWithCode(err error, errCode ErrorCode, msg string) error {
+	if isNil(err) {
+		return nil
+	}
+	return wrapError(err, WithMessage(msg), WithCode(errCode))
 }
 
 // The function "Cause" recursively retrieves the root cause of an error by checking if the error
 // implements the CustomError interface.
 func Cause(err error) error {
+	if isNil(err) {
+		return nil
+	}
 	if customErr, ok := err.(*CustomError); ok && customErr.Cause() != nil {
 		return Cause(customErr.Cause())
 	}
@@ -145,21 +573,58 @@ func Cause(err error) error {
 
 // The Unwrap function takes an error and return unwrapped error.
 func Unwrap(err error) error {
-	if customErr, ok := err.(*CustomError); ok && customErr != nil {
+	if customErr, ok := err.(*CustomError); ok {
 		return customErr.Cause()
 	}
 	return err
 }
 
+// Trace returns the printed trace of the first CustomError in err's chain, looking
+// through fmt.Errorf %w wrappers and joined errors.
 func Trace(err error) string {
-	if customErr, ok := err.(*CustomError); ok {
+	var customErr *CustomError
+	if errors.As(err, &customErr) {
 		return customErr.PrintTrace()
 	}
 	return ""
 }
 
+// TraceOf returns the capture points of the first CustomError in err's chain,
+// latest first, looking through fmt.Errorf %w wrappers and joined errors.
+func TraceOf(err error) []TracePoint {
+	var customErr *CustomError
+	if errors.As(err, &customErr) {
+		return customErr.TracePoints()
+	}
+	return nil
+}
+
+// CodeOf returns the code of the first CustomError in err's chain, looking through
+// fmt.Errorf %w wrappers and joined errors.
+func CodeOf(err error) (ErrorCode, bool) {
+	var customErr *CustomError
+	if !errors.As(err, &customErr) || customErr == nil {
+		return 0, false
+	}
+	return customErr.code, true
+}
+
 // IsCustomError checks if the error is a CustomError
 func IsCustomError(err error) bool {
-	_, ok := err.(*CustomError)
-	return ok
+	customErr, ok := err.(*CustomError)
+	return ok && customErr != nil
+}
+
+// newID returns a random hexadecimal error ID.
+func newID() string {
+	return fmt.Sprintf("%016x", rand.Uint64())
+}
+
+// isNil reports whether err is nil or a nil *CustomError stored in an error interface.
+func isNil(err error) bool {
+	if err == nil {
+		return true
+	}
+	customErr, ok := err.(*CustomError)
+	return ok && customErr == nil
 }
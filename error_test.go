@@ -0,0 +1,442 @@
+package exception
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestFields(t *testing.T) {
+	err := WrapMessage(io.EOF, "read user").(*CustomError)
+	WithFields(map[string]any{"user": 7, "tenant": "acme"})(err)
+	WithField("user", 8)(err)
+
+	tests := []struct {
+		key  string
+		want any
+		ok   bool
+	}{
+		{"user", 8, true},
+		{"tenant", "acme", true},
+		{"missing", nil, false},
+	}
+	for _, tt := range tests {
+		if got, ok := err.Field(tt.key); got != tt.want || ok != tt.ok {
+			t.Fatalf("Field(%q) = %v, %v, want %v, %v", tt.key, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	fields := err.Fields()
+	fields["user"] = 9
+	if got, _ := err.Field("user"); got != 8 {
+		t.Fatalf("Fields should return a copy, Field(user) = %v", got)
+	}
+	if New("boom", ErrorInternalServer).Fields() != nil {
+		t.Fatal("Fields of an error without fields should be nil")
+	}
+}
+
+func TestWrapIf(t *testing.T) {
+	var typedNil *CustomError
+	tests := []struct {
+		name    string
+		err     error
+		wantNil bool
+		code    ErrorCode
+	}{
+		{"nil", nil, true, 0},
+		{"typed nil", typedNil, true, 0},
+		{"plain error", io.EOF, false, ErrorInternalServer},
+		{"custom error", New("user missing", ErrorUserNotFound), false, ErrorInternalServer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WrapIf(tt.err, "load user")
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("WrapIf = %v, want nil", got)
+				}
+				if got := WrapIfWithCode(tt.err, ErrorDataNotFound, "load user"); got != nil {
+					t.Fatalf("WrapIfWithCode = %v, want nil", got)
+				}
+				return
+			}
+			customErr, ok := got.(*CustomError)
+			if !ok || customErr.Code() != tt.code || customErr.Error() != "load user" {
+				t.Fatalf("WrapIf = %#v", got)
+			}
+			if customErr.Trace == "" {
+				t.Fatal("WrapIf should capture a trace")
+			}
+			if got := WrapIfWithCode(tt.err, ErrorDataNotFound, "load user").(*CustomError); got.Code() != ErrorDataNotFound {
+				t.Fatalf("WrapIfWithCode code = %d", got.Code())
+			}
+		})
+	}
+}
+
+func TestNilCustomError(t *testing.T) {
+	var e *CustomError
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"Error", e.Error(), "<nil>"},
+		{"Cause", e.Cause(), error(nil)},
+		{"Code", e.Code(), ErrorCode(0)},
+		{"Unwrap", e.Unwrap(), error(nil)},
+		{"Is", e.Is(New("", ErrorInternalServer)), false},
+		{"PrintTrace", e.PrintTrace(), ""},
+		{"Fields", len(e.Fields()), 0},
+		{"IsCustomError", IsCustomError(e), false},
+		{"Cause helper", Cause(e), error(nil)},
+		{"Unwrap helper", Unwrap(e), error(nil)},
+		{"Trace helper", Trace(e), ""},
+		{"WrapMessage", WrapMessage(e, "wrapped"), error(nil)},
+		{"WrapMessageWithCode", WrapMessageWithCode(e, ErrorDataNotFound, "wrapped"), error(nil)},
+		{"WrapTrace", WrapTrace(e), error(nil)},
+		{"WrapMessage nil", WrapMessage(nil, "wrapped"), error(nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Fatalf("got %#v, want %#v", tt.got, tt.want)
+			}
+		})
+	}
+	if New("", ErrorInternalServer).Is(e) {
+		t.Fatal("Is should not match a nil target")
+	}
+}
+
+func TestOriginAndLatestTrace(t *testing.T) {
+	var nilErr *CustomError
+	tests := []struct {
+		name           string
+		err            *CustomError
+		origin, latest string
+	}{
+		{"nil", nilErr, "", ""},
+		{"no trace", New("boom", ErrorInternalServer), "", ""},
+		{"one capture", &CustomError{Trace: "a.go:1 f (x2)"}, "a.go:1 f", "a.go:1 f"},
+		{"several captures", &CustomError{Trace: "c.go:3 h", PreviousTraces: []string{"b.go:2 g", "a.go:1 f (x2)"}}, "a.go:1 f", "c.go:3 h"},
+		{"empty previous entries", &CustomError{Trace: "c.go:3 h", PreviousTraces: []string{"b.go:2 g", ""}}, "b.go:2 g", "c.go:3 h"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.OriginTrace(); got != tt.origin {
+				t.Fatalf("OriginTrace = %q, want %q", got, tt.origin)
+			}
+			if got := tt.err.LatestTrace(); got != tt.latest {
+				t.Fatalf("LatestTrace = %q, want %q", got, tt.latest)
+			}
+			if got := tt.err.Origin(); got != parseFrame(tt.origin) {
+				t.Fatalf("Origin = %+v", got)
+			}
+		})
+	}
+}
+
+func TestOriginOfWrappedError(t *testing.T) {
+	err := WrapMessage(io.EOF, "read")
+	err = WrapMessage(err, "load")
+	customErr := err.(*CustomError)
+	if got := customErr.Origin(); got.Function != "github.com/tae2089/exception.TestOriginOfWrappedError" || got.Line == 0 {
+		t.Fatalf("Origin = %+v", got)
+	}
+	if customErr.Origin() == parseFrame(customErr.LatestTrace()) {
+		t.Fatal("Origin should be the first capture point")
+	}
+}
+
+func TestCopyMethodsDoNotMutate(t *testing.T) {
+	orig := WrapMessageWithCode(io.EOF, ErrorDataNotFound, "user missing").(*CustomError)
+	WithField("user", 7)(orig)
+	WrapMessage(orig, "load user")
+	traces := len(orig.PreviousTraces)
+
+	tests := []struct {
+		name  string
+		copy  *CustomError
+		check func(*CustomError) bool
+	}{
+		{"WithCodeC", orig.WithCodeC(ErrorUserExists), func(c *CustomError) bool { return c.Code() == ErrorUserExists }},
+		{"WithFieldC", orig.WithFieldC("tenant", "acme"), func(c *CustomError) bool { v, _ := c.Field("tenant"); return v == "acme" }},
+		{"WithFieldC overwrite", orig.WithFieldC("user", 8), func(c *CustomError) bool { v, _ := c.Field("user"); return v == 8 }},
+		{"WithPublic", orig.WithPublic("not found"), func(c *CustomError) bool { return c.PublicMessage() == "not found" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.copy == orig {
+				t.Fatal("the method returned the receiver")
+			}
+			if !tt.check(tt.copy) {
+				t.Fatalf("copy = %+v", tt.copy)
+			}
+			if tt.copy.Message != orig.Message || tt.copy.Trace != orig.Trace || tt.copy.Err != orig.Err {
+				t.Fatalf("copy lost the error's message, trace or cause: %+v", tt.copy)
+			}
+			WrapMessage(tt.copy, "wrapped copy")
+		})
+	}
+
+	if orig.Code() != ErrorInternalServer || orig.PublicMessage() != "" || orig.Message != "load user" {
+		t.Fatalf("receiver changed: %+v", orig)
+	}
+	if v, _ := orig.Field("user"); v != 7 {
+		t.Fatalf("receiver field changed: %v", v)
+	}
+	if _, ok := orig.Field("tenant"); ok {
+		t.Fatal("receiver gained a field")
+	}
+	if len(orig.PreviousTraces) != traces {
+		t.Fatalf("wrapping a copy changed the receiver's traces: %q", orig.PreviousTraces)
+	}
+
+	var nilErr *CustomError
+	if nilErr.WithCodeC(ErrorInternalServer) != nil || nilErr.WithFieldC("k", 1) != nil || nilErr.WithPublic("x") != nil {
+		t.Fatal("copy methods on a nil CustomError should return nil")
+	}
+}
+
+func TestNewOptions(t *testing.T) {
+	err := New("user missing", ErrorUserNotFound, WithField("id", 7), WithDomain("users"), WithRemote())
+	if v, _ := err.Field("id"); v != 7 || err.Domain() != "users" || !err.IsRemote() {
+		t.Fatalf("New ignored options: %+v", err)
+	}
+	if err.Code() != ErrorUserNotFound || err.Error() != "user missing" {
+		t.Fatalf("New = %d %q", err.Code(), err.Error())
+	}
+}
+
+func TestCallOptionsDoNotLeakFromNew(t *testing.T) {
+	err := New("boom", ErrorInternalServer, WithNoTrace(), WithStackDepth(8), WithCallerSkip(1))
+	if err.noTrace || err.stackDepth != 0 || err.callerSkip != 0 {
+		t.Fatalf("per-call options kept after New: %+v", err.takeCallOptions())
+	}
+	wrapped := WrapMessage(err, "wrapped").(*CustomError)
+	if wrapped.Trace == "" {
+		t.Fatal("Wrap after New(WithNoTrace) should capture a trace")
+	}
+	if n := strings.Count(wrapped.Trace, "\n") + 1; n != 1 {
+		t.Fatalf("Wrap used a leaked stack depth: %d frames", n)
+	}
+}
+
+func TestWithStack(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		message string
+		code    ErrorCode
+	}{
+		{"plain error", io.EOF, "EOF", ErrorInternalServer},
+		{"custom error", New("user missing", ErrorUserNotFound), "user missing", ErrorUserNotFound},
+		{"lazy error", NewLazy(ErrorDataInvalid, "user %d invalid", 42), "user 42 invalid", ErrorDataInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customErr := WithStack(tt.err).(*CustomError)
+			if customErr.Error() != tt.message || customErr.Code() != tt.code {
+				t.Fatalf("WithStack = %q (%d), want %q (%d)", customErr.Error(), customErr.Code(), tt.message, tt.code)
+			}
+			if got := customErr.Origin().Function; got != "github.com/tae2089/exception.TestWithStack.func1" {
+				t.Fatalf("origin = %q", got)
+			}
+		})
+	}
+	if WithStack(nil) != nil {
+		t.Fatal("WithStack(nil) should return nil")
+	}
+}
+
+func TestWrapMessagefWithCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"plain error", io.EOF},
+		{"custom error", New("user missing", ErrorUserNotFound)},
+		{"lazy error", NewLazy(ErrorUserNotFound, "user %d missing", 42)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customErr := WrapMessagefWithCode(tt.err, ErrorDataNotFound, "load user %d", 42).(*CustomError)
+			if customErr.Error() != "load user 42" || customErr.Code() != ErrorDataNotFound {
+				t.Fatalf("WrapMessagefWithCode = %q (%d)", customErr.Error(), customErr.Code())
+			}
+			if got := parseFrame(customErr.LatestTrace()).Function; got != "github.com/tae2089/exception.TestWrapMessagefWithCode.func1" {
+				t.Fatalf("latest frame = %q", got)
+			}
+		})
+	}
+	if WrapMessagefWithCode(nil, ErrorDataNotFound, "load user %d", 42) != nil {
+		t.Fatal("WrapMessagefWithCode(nil) should return nil")
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	var nilErr *CustomError
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCode
+		ok   bool
+	}{
+		{"nil", nil, 0, false},
+		{"plain error", io.EOF, 0, false},
+		{"typed nil", nilErr, 0, false},
+		{"custom error", New("user missing", ErrorUserNotFound), ErrorUserNotFound, true},
+		{"fmt wrapped", fmt.Errorf("load: %w", New("user missing", ErrorUserNotFound)), ErrorUserNotFound, true},
+		{"joined", errors.Join(io.EOF, New("conflict", ErrorConflict)), ErrorConflict, true},
+		{"outermost wins", WrapMessageWithCode(fmt.Errorf("query: %w", New("user missing", ErrorUserNotFound)), ErrorDataNotFound, "load user"), ErrorDataNotFound, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := CodeOf(tt.err)
+			if got != tt.want || ok != tt.ok {
+				t.Fatalf("CodeOf = %d, %v, want %d, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestTraceOf(t *testing.T) {
+	var nilErr *CustomError
+	base := Wrap(io.EOF)
+	customErr := WrapMessage(base, "load user").(*CustomError)
+	tests := []struct {
+		name   string
+		err    error
+		points int
+	}{
+		{"nil", nil, 0},
+		{"plain error", io.EOF, 0},
+		{"typed nil", nilErr, 0},
+		{"custom error", customErr, 2},
+		{"fmt wrapped", fmt.Errorf("load: %w", customErr), 2},
+		{"joined", errors.Join(io.EOF, customErr), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points := TraceOf(tt.err)
+			if len(points) != tt.points {
+				t.Fatalf("TraceOf returned %d points, want %d", len(points), tt.points)
+			}
+			if tt.points == 0 {
+				if Trace(tt.err) != "" {
+					t.Fatalf("Trace = %q, want empty", Trace(tt.err))
+				}
+				return
+			}
+			if Trace(tt.err) != customErr.PrintTrace() {
+				t.Fatalf("Trace = %q, want %q", Trace(tt.err), customErr.PrintTrace())
+			}
+			if got := points[0].Frames[0]; got != parseFrame(customErr.LatestTrace()) {
+				t.Fatalf("latest frame = %s", got)
+			}
+			if got := points[len(points)-1].Frames[0]; got != customErr.Origin() {
+				t.Fatalf("origin frame = %s", got)
+			}
+			for _, point := range points {
+				if len(point.Frames) == 0 || point.Count < 1 {
+					t.Fatalf("point = %+v", point)
+				}
+			}
+		})
+	}
+}
+
+func TestWithNoTrace(t *testing.T) {
+	tests := []struct {
+		name string
+		err  func() *CustomError
+	}{
+		{"wrap", func() *CustomError { return Wrap(io.EOF, WithNoTrace()).(*CustomError) }},
+		{"with other options", func() *CustomError {
+			return Wrap(io.EOF, WithNoTrace(), WithMessage("read"), WithStackDepth(4)).(*CustomError)
+		}},
+		{"existing error", func() *CustomError { return Wrap(New("boom", ErrorInternalServer), WithNoTrace()).(*CustomError) }},
+		{"builder", func() *CustomError { return Build("boom").NoTrace().Err().(*CustomError) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customErr := tt.err()
+			if customErr.Trace != "" {
+				t.Fatalf("trace captured: %q", customErr.Trace)
+			}
+			Wrap(customErr)
+			if customErr.Trace == "" {
+				t.Fatal("the next Wrap should capture a trace")
+			}
+		})
+	}
+}
+
+func TestWrapTraceMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+		code ErrorCode
+	}{
+		{"plain error", io.EOF, "internal server error: EOF", ErrorInternalServer},
+		{"custom error", New("user 42 missing", ErrorNotFound), "user 42 missing", ErrorNotFound},
+		{"lazy message", NewLazy(ErrorNotFound, "user %d missing", 42), "user 42 missing", ErrorNotFound},
+		{"no message", New("", ErrorNotFound), "not found", ErrorNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WrapTrace(tt.err)
+			if got.Error() != tt.want {
+				t.Fatalf("WrapTrace = %q, want %q", got.Error(), tt.want)
+			}
+			if code, _ := CodeOf(got); code != tt.code {
+				t.Fatalf("code = %d, want %d", code, tt.code)
+			}
+			if tt.err == io.EOF && !errors.Is(got, io.EOF) {
+				t.Fatal("WrapTrace should keep the cause")
+			}
+			if Trace(got) == "" {
+				t.Fatal("WrapTrace should capture a trace")
+			}
+		})
+	}
+	if WrapTrace(nil) != nil {
+		t.Fatal("WrapTrace(nil) should be nil")
+	}
+}
+
+func TestIs(t *testing.T) {
+	notFound := New("", ErrorNotFound)
+	var nilErr *CustomError
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{"same code", New("user missing", ErrorUserNotFound), notFound, true},
+		{"other code", New("user missing", ErrorUserNotFound), New("", ErrorConflict), false},
+		{"wrapped sentinel", Wrap(sql.ErrNoRows, WithCode(ErrorNotFound)), sql.ErrNoRows, true},
+		{"through fmt", fmt.Errorf("load user: %w", Wrap(sql.ErrNoRows, WithCode(ErrorNotFound))), sql.ErrNoRows, true},
+		{"code through fmt", fmt.Errorf("load user: %w", Wrap(sql.ErrNoRows, WithCode(ErrorNotFound))), notFound, true},
+		{"joined cause", Wrap(errors.Join(io.EOF, sql.ErrNoRows), WithCode(ErrorNotFound)), sql.ErrNoRows, true},
+		{"code of joined cause", Wrap(errors.Join(io.EOF, sql.ErrNoRows), WithCode(ErrorNotFound)), notFound, true},
+		{"joined outer", errors.Join(io.EOF, Wrap(sql.ErrNoRows, WithCode(ErrorNotFound))), sql.ErrNoRows, true},
+		{"code in joined outer", errors.Join(io.EOF, Wrap(sql.ErrNoRows, WithCode(ErrorNotFound))), notFound, true},
+		{"unrelated sentinel", fmt.Errorf("outer: %w", Wrap(io.EOF, WithCode(ErrorInternalServer))), sql.ErrNoRows, false},
+		{"nil error", nilErr, io.EOF, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.target); got != tt.want {
+				t.Fatalf("errors.Is = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+// Package exceptionbackoff lets exception.CustomErrors drive cenkalti/backoff retries.
+package exceptionbackoff
+
+import (
+	"errors"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/tae2089/exception"
+)
+
+// Operation adapts op so that its errors dictate the retry behavior through
+// exception.RetryPolicy: non-retryable errors stop retrying with
+// backoff.Permanent, and errors requesting a delay are retried after it.
+func Operation[T any](op backoff.Operation[T]) backoff.Operation[T] {
+	return func() (T, error) {
+		result, err := op()
+		return result, Classify(err)
+	}
+}
+
+// Classify converts err to the error type backoff uses to control retries. The
+// result still unwraps to err, so it stays available through errors.As when
+// retries run out. Errors without a CustomError in their chain are returned
+// unchanged and retried.
+func Classify(err error) error {
+	var customErr *exception.CustomError
+	if !errors.As(err, &customErr) || customErr == nil {
+		return err
+	}
+	shouldRetry, after := exception.RetryPolicy(err)
+	switch {
+	case !shouldRetry:
+		return backoff.Permanent(err)
+	case after > 0:
+		return &retryAfterError{err: err, after: &backoff.RetryAfterError{Duration: after}}
+	}
+	return err
+}
+
+// retryAfterError carries the delay requested by err to backoff, which finds it
+// with errors.As, while reading as err.
+type retryAfterError struct {
+	err   error
+	after *backoff.RetryAfterError
+}
+
+func (e *retryAfterError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryAfterError) Unwrap() []error {
+	return []error{e.err, e.after}
+}
@@ -0,0 +1,84 @@
+package exceptionbackoff
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/tae2089/exception"
+)
+
+// fixed waits a long time between retries, unless an error requests a delay.
+var fixed = backoff.WithBackOff(backoff.NewConstantBackOff(time.Hour))
+
+const errorServiceUnavailable exception.ErrorCode = 503
+
+func TestOperation(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		calls int
+	}{
+		{"retry after", exception.Wrap(io.EOF, exception.WithCode(errorServiceUnavailable), exception.WithRetryAfter(time.Millisecond)), 3},
+		{"permanent", exception.New("bad input", exception.ErrorDataInvalid), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			op := Operation(func() (int, error) {
+				calls++
+				return 0, tt.err
+			})
+			_, err := backoff.Retry(context.Background(), op, fixed, backoff.WithMaxTries(3))
+			if calls != tt.calls {
+				t.Fatalf("calls = %d, want %d", calls, tt.calls)
+			}
+			var customErr *exception.CustomError
+			if !errors.As(err, &customErr) || customErr != tt.err {
+				t.Fatalf("err = %v, want the last CustomError", err)
+			}
+			if err.Error() != tt.err.Error() {
+				t.Fatalf("err = %q, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestClassify(t *testing.T) {
+	retryAfter := exception.New("slow down", exception.ErrorCode(429), exception.WithRetryAfter(time.Second))
+	tests := []struct {
+		name      string
+		err       error
+		same      bool
+		permanent bool
+		after     time.Duration
+	}{
+		{"nil", nil, true, false, 0},
+		{"plain error", io.EOF, true, false, 0},
+		{"transient code", exception.New("upstream down", errorServiceUnavailable), true, false, 0},
+		{"permanent", exception.New("bad input", exception.ErrorDataInvalid), false, true, 0},
+		{"retry after", retryAfter, false, false, time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Classify(tt.err)
+			if (got == tt.err) != tt.same {
+				t.Fatalf("Classify = %v, want the error unchanged: %v", got, tt.same)
+			}
+			var permanent *backoff.PermanentError
+			if errors.As(got, &permanent) != tt.permanent {
+				t.Fatalf("Classify = %#v, want permanent %v", got, tt.permanent)
+			}
+			var after *backoff.RetryAfterError
+			if errors.As(got, &after) != (tt.after > 0) || (tt.after > 0 && after.Duration != tt.after) {
+				t.Fatalf("Classify = %#v, want retry after %s", got, tt.after)
+			}
+			if tt.err != nil && !errors.Is(got, tt.err) {
+				t.Fatalf("Classify = %v, should unwrap to %v", got, tt.err)
+			}
+		})
+	}
+}
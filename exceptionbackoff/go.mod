@@ -0,0 +1,10 @@
+module github.com/tae2089/exception/exceptionbackoff
+
+go 1.24.5
+
+require (
+	github.com/cenkalti/backoff/v5 v5.0.3
+	github.com/tae2089/exception v0.1.0
+)
+
+replace github.com/tae2089/exception => ../
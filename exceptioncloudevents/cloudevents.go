@@ -0,0 +1,102 @@
+// Package exceptioncloudevents publishes exception.CustomErrors as CloudEvents,
+// so error events can travel over an event bus in a standard envelope.
+package exceptioncloudevents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/google/uuid"
+	"github.com/tae2089/exception"
+)
+
+// TypePrefix starts the type of every error event.
+const TypePrefix = "io.github.tae2089.exception.error"
+
+// Extension attributes set on error events.
+const (
+	ExtensionCode     = "errorcode"
+	ExtensionSeverity = "errorseverity"
+)
+
+// Type returns the event type for err: TypePrefix followed by the domain, if any,
+// and the code name, such as "io.github.tae2089.exception.error.billing.NotFound".
+func Type(err *exception.CustomError) string {
+	parts := []string{TypePrefix}
+	if domain := err.Domain(); domain != "" {
+		parts = append(parts, domain)
+	}
+	return strings.Join(append(parts, err.Code().Name()), ".")
+}
+
+// ToEvent converts the first CustomError in err's chain to an event from source.
+// The event ID is the error ID, the subject its fingerprint, the time its creation
+// time, and the data the error as written by exception.Encode. Errors without an
+// ID and shared errors, such as sentinels and interned errors, get a random event
+// ID instead, since their ID is not unique per occurrence. Errors without a
+// CustomError are converted as internal server errors caused by them.
+func ToEvent(err error, source string) (event.Event, error) {
+	var customErr *exception.CustomError
+	if !errors.As(err, &customErr) || customErr == nil {
+		var msg string
+		if err != nil {
+			msg = err.Error()
+		}
+		customErr = exception.New(msg, exception.ErrorInternalServer, exception.WithCause(err))
+	}
+	e := event.New()
+	id := customErr.ID()
+	if id == "" || customErr.Shared() {
+		id = uuid.NewString()
+	}
+	e.SetID(id)
+	e.SetSource(source)
+	e.SetType(Type(customErr))
+	e.SetSubject(customErr.Fingerprint())
+	if created := customErr.CreatedAt(); !created.IsZero() {
+		e.SetTime(created)
+	}
+	e.SetExtension(ExtensionCode, int32(customErr.Code()))
+	e.SetExtension(ExtensionSeverity, customErr.Severity().String())
+	data, encodeErr := exception.Encode(customErr)
+	if encodeErr != nil {
+		return event.Event{}, encodeErr
+	}
+	if setErr := e.SetData(event.ApplicationJSON, json.RawMessage(data)); setErr != nil {
+		return event.Event{}, setErr
+	}
+	return e, e.Validate()
+}
+
+// FromEvent rebuilds the remote error carried by an event written by ToEvent.
+func FromEvent(e event.Event) (*exception.CustomError, error) {
+	return exception.Decode(e.Data())
+}
+
+// Sender sends events, as a CloudEvents client does.
+type Sender interface {
+	Send(ctx context.Context, e event.Event) protocol.Result
+}
+
+// Sink returns an exception.Sink publishing every reported error through sender
+// as an event from source.
+func Sink(sender Sender, source string) exception.Sink {
+	return exception.SinkFunc(func(ctx context.Context, errs []*exception.CustomError) error {
+		var sendErrs []error
+		for _, customErr := range errs {
+			e, err := ToEvent(customErr, source)
+			if err != nil {
+				sendErrs = append(sendErrs, err)
+				continue
+			}
+			if result := sender.Send(ctx, e); !protocol.IsACK(result) {
+				sendErrs = append(sendErrs, result)
+			}
+		}
+		return errors.Join(sendErrs...)
+	})
+}
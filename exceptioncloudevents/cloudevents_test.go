@@ -0,0 +1,110 @@
+package exceptioncloudevents
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/tae2089/exception"
+)
+
+func TestType(t *testing.T) {
+	tests := []struct {
+		err  *exception.CustomError
+		want string
+	}{
+		{exception.New("missing", exception.ErrorNotFound), TypePrefix + ".NotFound"},
+		{exception.New("missing", exception.ErrorNotFound, exception.WithDomain("billing")), TypePrefix + ".billing.NotFound"},
+	}
+	for _, tt := range tests {
+		if got := Type(tt.err); got != tt.want {
+			t.Errorf("Type = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestToEvent(t *testing.T) {
+	wrapped := exception.Wrap(io.EOF, exception.WithCode(exception.ErrorNotFound), exception.WithDomain("users")).(*exception.CustomError)
+	sentinel := exception.RegisterSentinel(exception.ErrorUserExists, exception.New("user exists", 0))
+	interned := exception.NewInterner(8).New("rejected", exception.ErrorBadRequest)
+	remote, err := exception.Decode([]byte(`{"version": 2, "code": 409, "message": "remote"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		err       error
+		id        string
+		code      exception.ErrorCode
+		eventType string
+	}{
+		{"custom error", wrapped, wrapped.ID(), exception.ErrorNotFound, TypePrefix + ".users.NotFound"},
+		{"plain error", io.EOF, "", exception.ErrorInternalServer, TypePrefix + "." + exception.ErrorInternalServer.Name()},
+		{"nil error", nil, "", exception.ErrorInternalServer, TypePrefix + "." + exception.ErrorInternalServer.Name()},
+		{"shared error", sentinel, "", exception.ErrorUserExists, TypePrefix + "." + exception.ErrorUserExists.Name()},
+		{"interned error", interned, "", exception.ErrorBadRequest, TypePrefix + "." + exception.ErrorBadRequest.Name()},
+		{"error without an ID", remote, "", exception.ErrorConflict, TypePrefix + "." + exception.ErrorConflict.Name()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, convertErr := ToEvent(tt.err, "/users")
+			if convertErr != nil {
+				t.Fatal(convertErr)
+			}
+			if tt.id != "" && e.ID() != tt.id || e.ID() == "" {
+				t.Fatalf("event ID = %q, want %q", e.ID(), tt.id)
+			}
+			// 공유 에러는 발생마다 새 이벤트 ID를 받음
+			if shared, ok := tt.err.(*exception.CustomError); ok && shared.Shared() && e.ID() == shared.ID() {
+				t.Fatal("a shared error should get a fresh event ID")
+			}
+			if e.Source() != "/users" || e.Type() != tt.eventType || e.Extensions()[ExtensionCode] != int32(tt.code) {
+				t.Fatalf("event = %s", e)
+			}
+			decoded, decodeErr := FromEvent(e)
+			if decodeErr != nil {
+				t.Fatal(decodeErr)
+			}
+			if decoded.Code() != tt.code || !decoded.IsRemote() || tt.id != "" && decoded.ID() != tt.id {
+				t.Fatalf("decoded = %s %d", decoded.ID(), decoded.Code())
+			}
+		})
+	}
+}
+
+// senderFunc adapts a function to Sender.
+type senderFunc func(ctx context.Context, e event.Event) protocol.Result
+
+func (f senderFunc) Send(ctx context.Context, e event.Event) protocol.Result { return f(ctx, e) }
+
+func TestSink(t *testing.T) {
+	nack := protocol.NewReceipt(false, "rejected")
+	tests := []struct {
+		name    string
+		result  protocol.Result
+		wantErr bool
+	}{
+		{"ack", protocol.ResultACK, false},
+		{"nack", nack, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []event.Event
+			sink := Sink(senderFunc(func(_ context.Context, e event.Event) protocol.Result {
+				sent = append(sent, e)
+				return tt.result
+			}), "/users")
+			errs := []*exception.CustomError{exception.New("a", exception.ErrorNotFound), exception.New("b", exception.ErrorConflict)}
+			err := sink.Send(context.Background(), errs)
+			if (err != nil) != tt.wantErr || tt.wantErr && !errors.Is(err, nack) {
+				t.Fatalf("Send = %v", err)
+			}
+			if len(sent) != 2 || sent[0].ID() != errs[0].ID() || sent[1].ID() != errs[1].ID() {
+				t.Fatalf("sent %d events", len(sent))
+			}
+		})
+	}
+}
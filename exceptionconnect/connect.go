@@ -0,0 +1,145 @@
+// Package exceptionconnect provides connect-go interceptors that carry
+// exception.CustomError codes and traces across connect-rpc calls.
+package exceptionconnect
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	"github.com/tae2089/exception"
+	"github.com/tae2089/exception/exceptiongrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ToConnect converts err to a *connect.Error whose code is derived from the first
+// CustomError in the chain. Its message and details hold what
+// exceptiongrpc.PublicMessage and exceptiongrpc.Detail expose to clients.
+// A connect error in the chain is returned unchanged unless a local CustomError
+// wraps it, in which case the CustomError's code and message win.
+func ToConnect(err error) *connect.Error {
+	if err == nil {
+		return nil
+	}
+	var customErr *exception.CustomError
+	found := errors.As(err, &customErr) && customErr != nil
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		// 원격 에러이거나 connect 에러 안쪽에 있는 CustomError는 connect 에러를 그대로 전달
+		var inner *exception.CustomError
+		if !found || customErr.IsRemote() || errors.As(connectErr, &inner) && inner == customErr {
+			return connectErr
+		}
+	}
+	if !found {
+		return connect.NewError(connect.CodeUnknown, &publicError{msg: exceptiongrpc.PublicMessage(err), err: err})
+	}
+	connectErr = connect.NewError(connect.Code(exceptiongrpc.CodeToGRPC(customErr.Code())), &publicError{msg: exceptiongrpc.PublicMessage(err), err: err})
+	if detail, derr := exceptiongrpc.Detail(err); derr == nil {
+		if d, derr := connect.NewErrorDetail(detail); derr == nil {
+			connectErr.AddDetail(d)
+		}
+	}
+	return connectErr
+}
+
+// FromConnect rebuilds the remote CustomError carried by a *connect.Error
+// received by a client. Errors without an encoded detail are rebuilt from their
+// code and message.
+func FromConnect(connectErr *connect.Error) *exception.CustomError {
+	if connectErr == nil {
+		return nil
+	}
+	for _, d := range connectErr.Details() {
+		msg, err := d.Value()
+		if err != nil {
+			continue
+		}
+		if s, ok := msg.(*structpb.Struct); ok {
+			if customErr, ok := exceptiongrpc.FromDetail(s); ok {
+				return customErr
+			}
+		}
+	}
+	return exception.New(connectErr.Message(), exceptiongrpc.CodeFromGRPC(codes.Code(connectErr.Code())), exception.WithRemote())
+}
+
+// NewHandlerInterceptor returns a server-side interceptor converting errors
+// returned by handlers with ToConnect.
+func NewHandlerInterceptor() connect.Interceptor {
+	return handlerInterceptor{}
+}
+
+type handlerInterceptor struct{}
+
+func (handlerInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		resp, err := next(ctx, req)
+		return resp, toConnectError(err)
+	}
+}
+
+func (handlerInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (handlerInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		return toConnectError(next(ctx, conn))
+	}
+}
+
+// NewClientInterceptor returns a client-side interceptor rebuilding CustomErrors
+// from the connect errors of unary calls with FromConnect. The connect error stays
+// in the chain of the returned error, so connect.CodeOf and errors.As keep working.
+func NewClientInterceptor() connect.Interceptor {
+	return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			resp, err := next(ctx, req)
+			var connectErr *connect.Error
+			if errors.As(err, &connectErr) {
+				return resp, &clientError{err: FromConnect(connectErr), connectErr: connectErr}
+			}
+			return resp, err
+		}
+	})
+}
+
+// clientError unwraps to both the rebuilt CustomError and the connect error it
+// was rebuilt from.
+type clientError struct {
+	err        *exception.CustomError
+	connectErr *connect.Error
+}
+
+func (e *clientError) Error() string {
+	return e.err.Error()
+}
+
+func (e *clientError) Unwrap() []error {
+	return []error{e.err, e.connectErr}
+}
+
+// publicError replaces the message of err with the one sent to clients while
+// keeping err in the chain for server-side interceptors.
+type publicError struct {
+	msg string
+	err error
+}
+
+func (e *publicError) Error() string {
+	return e.msg
+}
+
+func (e *publicError) Unwrap() error {
+	return e.err
+}
+
+// toConnectError keeps a nil error nil when converting it with ToConnect.
+func toConnectError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return ToConnect(err)
+}
@@ -0,0 +1,118 @@
+package exceptionconnect
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/tae2089/exception"
+	"github.com/tae2089/exception/exceptiongrpc"
+)
+
+func callWithError(t *testing.T, err error) error {
+	t.Helper()
+	interceptor := NewClientInterceptor()
+	call := interceptor.WrapUnary(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		return nil, err
+	})
+	_, callErr := call(context.Background(), connect.NewRequest(&struct{}{}))
+	return callErr
+}
+
+func TestToConnect(t *testing.T) {
+	existing := connect.NewError(connect.CodeAborted, errors.New("aborted"))
+	tests := []struct {
+		name    string
+		err     error
+		code    connect.Code
+		message string
+		details int
+	}{
+		{"custom error", exception.Wrap(io.EOF, exception.WithCode(exception.ErrorUserNotFound), exception.WithMessage("user 42 missing")), connect.CodeNotFound, "NotFound", 1},
+		{"public message", exception.New("db down", exception.ErrorInternalServer, exception.WithPublicMessage("try again")), connect.CodeInternal, "try again", 1},
+		{"plain error", errors.New("db: boom"), connect.CodeUnknown, "Unknown", 0},
+		{"connect error", existing, connect.CodeAborted, "aborted", 0},
+		{"wrapped connect error", exception.Wrap(existing, exception.WithCode(exception.ErrorUserNotFound)), connect.CodeNotFound, "NotFound", 1},
+		{"connect error wrapping a custom error", connect.NewError(connect.CodeAborted, exception.New("conflict", exception.ErrorConflict)), connect.CodeAborted, "conflict", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connectErr := ToConnect(tt.err)
+			if connectErr.Code() != tt.code || connectErr.Message() != tt.message || len(connectErr.Details()) != tt.details {
+				t.Fatalf("ToConnect = %v %q, %d details", connectErr.Code(), connectErr.Message(), len(connectErr.Details()))
+			}
+			if !errors.Is(connectErr, tt.err) && connectErr != tt.err {
+				t.Fatal("the original error should stay in the chain")
+			}
+		})
+	}
+	if ToConnect(nil) != nil {
+		t.Fatal("ToConnect(nil) should be nil")
+	}
+}
+
+func TestClientInterceptorKeepsConnectError(t *testing.T) {
+	sent := ToConnect(exception.New("user missing", exception.ErrorUserNotFound, exception.WithPublicMessage("no such user"), exception.WithDomain("users")))
+	err := callWithError(t, sent)
+
+	var customErr *exception.CustomError
+	if !errors.As(err, &customErr) || customErr.Code() != exception.ErrorUserNotFound || !customErr.IsRemote() || customErr.Domain() != "users" {
+		t.Fatalf("CustomError = %v", customErr)
+	}
+	if err.Error() != "no such user" {
+		t.Fatalf("Error = %q", err.Error())
+	}
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) || connectErr != sent {
+		t.Fatal("errors.As should find the connect error")
+	}
+	if code := connect.CodeOf(err); code != connect.CodeNotFound {
+		t.Fatalf("connect.CodeOf = %v", code)
+	}
+}
+
+func TestClientInterceptorExposedChain(t *testing.T) {
+	exceptiongrpc.SetExposeChain(true)
+	t.Cleanup(func() { exceptiongrpc.SetExposeChain(false) })
+
+	err := callWithError(t, ToConnect(exception.New("user missing", exception.ErrorUserNotFound, exception.WithField("id", "42"))))
+	var customErr *exception.CustomError
+	if !errors.As(err, &customErr) || customErr.Error() != "user missing" {
+		t.Fatalf("CustomError = %v", customErr)
+	}
+	if v, _ := customErr.Field("id"); v != "42" {
+		t.Fatalf("field id = %v", v)
+	}
+}
+
+func TestClientInterceptorWithoutDetail(t *testing.T) {
+	err := callWithError(t, connect.NewError(connect.CodeUnavailable, errors.New("draining")))
+	var customErr *exception.CustomError
+	if !errors.As(err, &customErr) || customErr.Code() != 503 || customErr.Error() != "draining" {
+		t.Fatalf("CustomError = %v", customErr)
+	}
+	if connect.CodeOf(err) != connect.CodeUnavailable {
+		t.Fatalf("connect.CodeOf = %v", connect.CodeOf(err))
+	}
+	if err := callWithError(t, nil); err != nil {
+		t.Fatalf("err = %v, want nil", err)
+	}
+}
+
+func TestHandlerInterceptor(t *testing.T) {
+	call := NewHandlerInterceptor().WrapUnary(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		return nil, exception.New("user missing", exception.ErrorUserNotFound)
+	})
+	_, err := call(context.Background(), connect.NewRequest(&struct{}{}))
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Fatalf("connect.CodeOf = %v", connect.CodeOf(err))
+	}
+	ok := NewHandlerInterceptor().WrapUnary(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		return nil, nil
+	})
+	if _, err := ok(context.Background(), connect.NewRequest(&struct{}{})); err != nil {
+		t.Fatalf("err = %v, want nil", err)
+	}
+}
@@ -0,0 +1,125 @@
+// Package exceptionelastic translates Elasticsearch and OpenSearch error
+// responses into coded exception.CustomErrors. It reads the error body shared by
+// both engines, so it works with their Go clients without depending on them.
+package exceptionelastic
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/tae2089/exception"
+)
+
+// Field keys attached to translated errors.
+const (
+	FieldIndex = "elasticsearch.index"
+	FieldType  = "elasticsearch.type"
+)
+
+// maxBody bounds how much of an error response is read.
+const maxBody = 64 << 10
+
+type errorCause struct {
+	Type      string       `json:"type"`
+	Reason    string       `json:"reason"`
+	Index     string       `json:"index"`
+	RootCause []errorCause `json:"root_cause"`
+}
+
+type errorBody struct {
+	Error  json.RawMessage `json:"error"`
+	Status int             `json:"status"`
+}
+
+// Code returns the ErrorCode for an error of the given type and HTTP status:
+// missing indexes and documents are 404, version conflicts and existing
+// resources 409, tripped circuit breakers and rejected executions 429, and other
+// errors keep their status.
+func Code(errorType string, status int) exception.ErrorCode {
+	switch errorType {
+	case "index_not_found_exception", "resource_not_found_exception", "document_missing_exception":
+		return exception.ErrorNotFound
+	case "version_conflict_engine_exception", "resource_already_exists_exception":
+		return exception.ErrorConflict
+	case "circuit_breaking_exception", "es_rejected_execution_exception":
+		return exception.ErrorTooManyRequests
+	}
+	if status < http.StatusBadRequest {
+		return exception.ErrorInternalServer
+	}
+	return exception.ErrorCode(status)
+}
+
+// FromResponse converts an error response with the given status and body, as
+// returned by esapi or opensearchapi, to a CustomError. The message is the error
+// reason, the index and error type are attached as fields, and the trace starts
+// at the caller. It returns nil for statuses below 400. Up to 64 KiB of body is
+// read; body may be nil.
+func FromResponse(status int, body io.Reader) *exception.CustomError {
+	if status < http.StatusBadRequest {
+		return nil
+	}
+	var data []byte
+	if body != nil {
+		data, _ = io.ReadAll(io.LimitReader(body, maxBody))
+	}
+	msg, code, opts := fromBody(status, data)
+	// Translate와 같이 호출한 곳의 추적을 기록
+	return exception.Wrap(exception.New(msg, code, opts...), exception.WithCallerSkip(1)).(*exception.CustomError)
+}
+
+// Translate converts an error returned by a typed client, such as the
+// ElasticsearchError of go-elasticsearch, to a CustomError when it serializes to
+// the error body of a response. Other errors are returned unchanged.
+func Translate(err error) error {
+	if err == nil {
+		return nil
+	}
+	var customErr *exception.CustomError
+	if errors.As(err, &customErr) {
+		return err
+	}
+	data, marshalErr := json.Marshal(err)
+	if marshalErr != nil {
+		return err
+	}
+	var body errorBody
+	if json.Unmarshal(data, &body) != nil || body.Status < http.StatusBadRequest || len(body.Error) == 0 {
+		return err
+	}
+	msg, code, opts := fromBody(body.Status, data)
+	opts = append(opts, exception.WithMessage(msg), exception.WithCode(code), exception.WithCallerSkip(1))
+	return exception.Wrap(err, opts...)
+}
+
+// fromBody returns the message, code and options of the error an error response
+// translates to, so the error is created, and audited, only once.
+func fromBody(status int, data []byte) (string, exception.ErrorCode, []exception.CustomErrorOption) {
+	var body errorBody
+	_ = json.Unmarshal(data, &body)
+	var cause errorCause
+	if json.Unmarshal(body.Error, &cause) != nil {
+		// 일부 응답은 error 필드가 문자열
+		_ = json.Unmarshal(body.Error, &cause.Reason)
+	}
+	if cause.Reason == "" && len(cause.RootCause) > 0 {
+		cause.Reason = cause.RootCause[0].Reason
+	}
+	if cause.Index == "" && len(cause.RootCause) > 0 {
+		cause.Index = cause.RootCause[0].Index
+	}
+	code := Code(cause.Type, status)
+	var opts []exception.CustomErrorOption
+	if cause.Index != "" {
+		opts = append(opts, exception.WithField(FieldIndex, cause.Index))
+	}
+	if cause.Type != "" {
+		opts = append(opts, exception.WithField(FieldType, cause.Type))
+	}
+	if code == exception.ErrorTooManyRequests {
+		opts = append(opts, exception.WithRetryable(true))
+	}
+	return cause.Reason, code, opts
+}
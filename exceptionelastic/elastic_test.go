@@ -0,0 +1,153 @@
+package exceptionelastic
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/tae2089/exception"
+)
+
+const notFoundBody = `{"error":{"root_cause":[{"type":"index_not_found_exception","reason":"no such index [users]","index":"users"}],"type":"index_not_found_exception","reason":"no such index [users]","index":"users"},"status":404}`
+
+// clientError mimics the typed error of go-elasticsearch, which serializes to the
+// response body.
+type clientError struct {
+	body string
+}
+
+func (e *clientError) Error() string { return "elasticsearch: " + e.body }
+
+func (e *clientError) MarshalJSON() ([]byte, error) { return []byte(e.body), nil }
+
+func auditEvents(t *testing.T) *[]exception.AuditEvent {
+	t.Helper()
+	var events []exception.AuditEvent
+	exception.AuditCodes(exception.ErrorNotFound)
+	exception.SetAuditSink(exception.AuditSinkFunc(func(event exception.AuditEvent) {
+		events = append(events, event)
+	}))
+	t.Cleanup(func() { exception.SetAuditSink(nil) })
+	return &events
+}
+
+// audits returns how many audit events an error with code should produce.
+func audits(code exception.ErrorCode) int {
+	if code == exception.ErrorNotFound {
+		return 1
+	}
+	return 0
+}
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		errorType string
+		status    int
+		want      exception.ErrorCode
+	}{
+		{"index_not_found_exception", 404, exception.ErrorNotFound},
+		{"document_missing_exception", 404, exception.ErrorNotFound},
+		{"version_conflict_engine_exception", 409, exception.ErrorConflict},
+		{"resource_already_exists_exception", 400, exception.ErrorConflict},
+		{"circuit_breaking_exception", 503, exception.ErrorTooManyRequests},
+		{"es_rejected_execution_exception", 429, exception.ErrorTooManyRequests},
+		{"parsing_exception", 400, exception.ErrorBadRequest},
+		{"", 200, exception.ErrorInternalServer},
+	}
+	for _, tt := range tests {
+		if got := Code(tt.errorType, tt.status); got != tt.want {
+			t.Errorf("Code(%q, %d) = %d, want %d", tt.errorType, tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestFromResponse(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      io.Reader
+		code      exception.ErrorCode
+		message   string
+		index     any
+		retryable bool
+	}{
+		{"index not found", 404, strings.NewReader(notFoundBody), exception.ErrorNotFound, "no such index [users]", "users", false},
+		{"root cause only", 400, strings.NewReader(`{"error":{"root_cause":[{"type":"x","reason":"bad query","index":"logs"}]},"status":400}`),
+			exception.ErrorBadRequest, "bad query", "logs", false},
+		{"string error", 500, strings.NewReader(`{"error":"node failure","status":500}`), exception.ErrorInternalServer, "node failure", nil, false},
+		{"rejected", 429, strings.NewReader(`{"error":{"type":"es_rejected_execution_exception","reason":"rejected"},"status":429}`),
+			exception.ErrorTooManyRequests, "rejected", nil, true},
+		{"no body", 503, nil, exception.ErrorServiceUnavailable, "service unavailable", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := auditEvents(t)
+			err := FromResponse(tt.status, tt.body)
+			if err.Code() != tt.code || err.Error() != tt.message {
+				t.Fatalf("err = %d %q, want %d %q", err.Code(), err.Error(), tt.code, tt.message)
+			}
+			if v, _ := err.Field(FieldIndex); v != tt.index {
+				t.Fatalf("%s = %v, want %v", FieldIndex, v, tt.index)
+			}
+			if exception.IsRetryable(err) != tt.retryable {
+				t.Fatalf("IsRetryable = %v", !tt.retryable)
+			}
+			if !strings.HasSuffix(err.Origin().File, "elastic_test.go") {
+				t.Fatalf("Origin = %s, want the caller", err.Origin())
+			}
+			// 감사 대상 코드는 한 번만 감사
+			if want := audits(tt.code); len(*events) != want {
+				t.Fatalf("audited %d times, want %d", len(*events), want)
+			}
+		})
+	}
+	if FromResponse(200, nil) != nil {
+		t.Fatal("FromResponse should return nil for a success status")
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	plain := errors.New("connection refused")
+	tests := []struct {
+		name      string
+		err       error
+		code      exception.ErrorCode
+		unchanged bool
+	}{
+		{"client error", &clientError{body: notFoundBody}, exception.ErrorNotFound, false},
+		{"rejected", &clientError{body: `{"error":{"type":"es_rejected_execution_exception","reason":"rejected"},"status":429}`},
+			exception.ErrorTooManyRequests, false},
+		{"success body", &clientError{body: `{"status":200}`}, 0, true},
+		{"not JSON", plain, 0, true},
+		{"custom error", exception.New("boom", exception.ErrorConflict), 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := auditEvents(t)
+			err := Translate(tt.err)
+			if tt.unchanged {
+				if err != tt.err {
+					t.Fatalf("Translate = %v, want the error unchanged", err)
+				}
+				return
+			}
+			var customErr *exception.CustomError
+			if !errors.As(err, &customErr) || customErr.Code() != tt.code {
+				t.Fatalf("err = %v", err)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatal("Translate should keep the client error as the cause")
+			}
+			if want := audits(tt.code); len(*events) != want || want == 1 && (*events)[0].ID != customErr.ID() {
+				t.Fatalf("audit events = %+v, want %d for the returned error", *events, want)
+			}
+			if !strings.HasSuffix(customErr.Origin().File, "elastic_test.go") {
+				t.Fatalf("Origin = %s", customErr.Origin())
+			}
+		})
+	}
+	if Translate(nil) != nil {
+		t.Fatal("Translate(nil) should be nil")
+	}
+}
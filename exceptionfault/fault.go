@@ -0,0 +1,90 @@
+// Package exceptionfault makes named injection points return configured
+// exception.CustomErrors, so retry, fallback and response-rendering paths can be
+// exercised in tests. Code under test calls Inject at each point; the points fire
+// only once armed on an Injector, and the package-level Inject is compiled in only
+// with the faultinject build tag.
+package exceptionfault
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sync"
+
+	"github.com/tae2089/exception"
+)
+
+// FieldPoint is the field key holding the name of the injection point that
+// produced an error.
+const FieldPoint = "fault.point"
+
+// Fault describes the error an armed injection point returns.
+type Fault struct {
+	Code    exception.ErrorCode
+	Message string
+	// Probability is the chance, between 0 and 1, that the point fires on each
+	// call. Zero or less fires on every call.
+	Probability float64
+	// Options are applied to every injected error, e.g. exception.WithRetryAfter.
+	Options []exception.CustomErrorOption
+}
+
+// Injector holds the faults armed at named injection points. It is safe for
+// concurrent use.
+type Injector struct {
+	mu     sync.RWMutex
+	faults map[string]Fault
+}
+
+// NewInjector creates an injector with no armed points.
+func NewInjector() *Injector {
+	return &Injector{faults: make(map[string]Fault)}
+}
+
+// Arm makes point return an error described by fault.
+func (i *Injector) Arm(point string, fault Fault) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults[point] = fault
+}
+
+// Disarm stops point from returning errors.
+func (i *Injector) Disarm(point string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.faults, point)
+}
+
+// Reset disarms every point.
+func (i *Injector) Reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	clear(i.faults)
+}
+
+// Inject returns a new error for point if it is armed and fires, and nil otherwise.
+// The error carries the caller's trace and the point name under FieldPoint.
+func (i *Injector) Inject(point string) error {
+	return i.inject(point)
+}
+
+func (i *Injector) inject(point string) error {
+	if i == nil {
+		return nil
+	}
+	i.mu.RLock()
+	fault, ok := i.faults[point]
+	i.mu.RUnlock()
+	if !ok || (fault.Probability > 0 && rand.Float64() >= fault.Probability) {
+		return nil
+	}
+	err := exception.New(fault.Message, fault.Code, append(slices.Clip(fault.Options), exception.WithField(FieldPoint, point))...)
+	// inject와 공개 Inject 함수를 건너뛰어 호출 위치를 기록
+	return exception.Wrap(err, exception.WithCallerSkip(2))
+}
+
+var defaultInjector = NewInjector()
+
+// Default returns the injector used by the package-level Inject.
+func Default() *Injector {
+	return defaultInjector
+}
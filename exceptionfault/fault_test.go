@@ -0,0 +1,88 @@
+package exceptionfault
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tae2089/exception"
+)
+
+func TestInjector(t *testing.T) {
+	tests := []struct {
+		name  string
+		arm   map[string]Fault
+		point string
+		code  exception.ErrorCode
+	}{
+		{"not armed", nil, "db.query", 0},
+		{"armed", map[string]Fault{"db.query": {Code: exception.ErrorServiceUnavailable, Message: "db down"}}, "db.query", exception.ErrorServiceUnavailable},
+		{"other point", map[string]Fault{"cache.get": {Code: exception.ErrorServiceUnavailable}}, "db.query", 0},
+		{"always fires", map[string]Fault{"db.query": {Code: exception.ErrorConflict, Probability: 1}}, "db.query", exception.ErrorConflict},
+		{"never fires", map[string]Fault{"db.query": {Code: exception.ErrorConflict, Probability: 1e-300}}, "db.query", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			injector := NewInjector()
+			for point, fault := range tt.arm {
+				injector.Arm(point, fault)
+			}
+			err := injector.Inject(tt.point)
+			if tt.code == 0 {
+				if err != nil {
+					t.Fatalf("Inject = %v, want nil", err)
+				}
+				return
+			}
+			customErr, ok := err.(*exception.CustomError)
+			if !ok || customErr.Code() != tt.code || customErr.Fields()[FieldPoint] != tt.point {
+				t.Fatalf("Inject = %v", err)
+			}
+			if got := customErr.Origin().Function; !strings.HasPrefix(got, "github.com/tae2089/exception/exceptionfault.TestInjector") {
+				t.Fatalf("trace starts at %q, want the Inject caller", got)
+			}
+		})
+	}
+}
+
+func TestInjectorOptions(t *testing.T) {
+	injector := NewInjector()
+	options := []exception.CustomErrorOption{exception.WithRetryAfter(time.Second)}
+	injector.Arm("api.call", Fault{Code: exception.ErrorTooManyRequests, Message: "slow down", Options: options})
+	for range 2 {
+		err := injector.Inject("api.call").(*exception.CustomError)
+		if err.Error() != "slow down" || err.RetryAfter() != time.Second {
+			t.Fatalf("Inject = %q, retry after %s", err.Error(), err.RetryAfter())
+		}
+	}
+	if len(options) != 1 {
+		t.Fatalf("Inject changed the fault options: %d", len(options))
+	}
+}
+
+func TestInjectorDisarm(t *testing.T) {
+	tests := []struct {
+		name    string
+		disarm  func(*Injector)
+		queryOn bool
+		cacheOn bool
+	}{
+		{"disarm one", func(i *Injector) { i.Disarm("db.query") }, false, true},
+		{"reset", (*Injector).Reset, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			injector := NewInjector()
+			injector.Arm("db.query", Fault{Code: exception.ErrorServiceUnavailable})
+			injector.Arm("cache.get", Fault{Code: exception.ErrorServiceUnavailable})
+			tt.disarm(injector)
+			if (injector.Inject("db.query") != nil) != tt.queryOn || (injector.Inject("cache.get") != nil) != tt.cacheOn {
+				t.Fatal("unexpected armed points")
+			}
+		})
+	}
+	var nilInjector *Injector
+	if nilInjector.Inject("db.query") != nil {
+		t.Fatal("a nil injector should inject nothing")
+	}
+}
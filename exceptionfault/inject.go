@@ -0,0 +1,11 @@
+//go:build faultinject
+
+package exceptionfault
+
+// Enabled reports whether the package-level Inject is compiled in.
+const Enabled = true
+
+// Inject returns the error armed for point on the default injector, if any.
+func Inject(point string) error {
+	return defaultInjector.inject(point)
+}
@@ -0,0 +1,11 @@
+//go:build !faultinject
+
+package exceptionfault
+
+// Enabled reports whether the package-level Inject is compiled in.
+const Enabled = false
+
+// Inject always returns nil; build with the faultinject tag to enable it.
+func Inject(string) error {
+	return nil
+}
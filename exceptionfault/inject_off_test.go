@@ -0,0 +1,23 @@
+//go:build !faultinject
+
+package exceptionfault
+
+import (
+	"testing"
+
+	"github.com/tae2089/exception"
+)
+
+func TestInjectDisabled(t *testing.T) {
+	if Enabled {
+		t.Fatal("Enabled should be false without the faultinject tag")
+	}
+	t.Cleanup(Default().Reset)
+	Default().Arm("db.query", Fault{Code: exception.ErrorServiceUnavailable})
+	if err := Inject("db.query"); err != nil {
+		t.Fatalf("Inject = %v, want nil without the faultinject tag", err)
+	}
+	if Default().Inject("db.query") == nil {
+		t.Fatal("the default injector should still fire when used directly")
+	}
+}
@@ -0,0 +1,29 @@
+//go:build faultinject
+
+package exceptionfault
+
+import (
+	"testing"
+
+	"github.com/tae2089/exception"
+)
+
+func TestInject(t *testing.T) {
+	if !Enabled {
+		t.Fatal("Enabled should be true with the faultinject tag")
+	}
+	t.Cleanup(Default().Reset)
+	Default().Arm("db.query", Fault{Code: exception.ErrorServiceUnavailable})
+	tests := []struct {
+		point string
+		fires bool
+	}{
+		{"db.query", true},
+		{"cache.get", false},
+	}
+	for _, tt := range tests {
+		if got := Inject(tt.point); (got != nil) != tt.fires {
+			t.Errorf("Inject(%q) = %v, want fired %v", tt.point, got, tt.fires)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Package exceptiongateway renders exception.CustomErrors returned through
+// grpc-gateway with exception.WriteHTTP.
+package exceptiongateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/tae2089/exception"
+	"github.com/tae2089/exception/exceptiongrpc"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorHandler is a runtime.ErrorHandlerFunc that recognizes CustomErrors encoded
+// in gRPC status details, or returned directly, and writes them with
+// exception.WriteHTTP. Other errors fall back to runtime.DefaultHTTPErrorHandler.
+//
+//	mux := runtime.NewServeMux(runtime.WithErrorHandler(exceptiongateway.ErrorHandler))
+func ErrorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	if st, ok := status.FromError(err); ok {
+		if customErr, ok := exceptiongrpc.FromStatus(st); ok {
+			exception.WriteHTTP(w, customErr)
+			return
+		}
+	}
+	var customErr *exception.CustomError
+	if errors.As(err, &customErr) {
+		exception.WriteHTTP(w, customErr)
+		return
+	}
+	runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
+}
+
+var _ runtime.ErrorHandlerFunc = ErrorHandler
@@ -0,0 +1,55 @@
+package exceptiongateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/tae2089/exception"
+	"github.com/tae2089/exception/exceptiongrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorHandler(t *testing.T) {
+	notFound := exception.New("user 42 missing", exception.ErrorUserNotFound,
+		exception.WithPublicMessage("user not found"), exception.WithID("e-1"), exception.WithDomain("users"))
+	internal := exception.New("query users: dial 10.0.3.7:5432 failed", exception.ErrorInternalServer, exception.WithID("e-2"))
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		message string
+		id      string
+	}{
+		{"status detail", exceptiongrpc.ToStatus(notFound).Err(), http.StatusNotFound, "user not found", "e-1"},
+		{"custom error", notFound, http.StatusNotFound, "user not found", "e-1"},
+		// 공개 메시지가 없으면 내부 메시지 대신 기본 메시지
+		{"status detail without public message", exceptiongrpc.ToStatus(internal).Err(), http.StatusInternalServerError, "internal server error", "e-2"},
+		{"custom error without public message", internal, http.StatusInternalServerError, "internal server error", "e-2"},
+		{"status without detail", status.Error(codes.Unavailable, "down"), http.StatusServiceUnavailable, "down", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+			ErrorHandler(context.Background(), runtime.NewServeMux(), &runtime.JSONPb{}, rec, req, tt.err)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			var body struct {
+				ID      string `json:"id"`
+				Message string `json:"message"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Message != tt.message || body.ID != tt.id {
+				t.Fatalf("body = %s", rec.Body.String())
+			}
+		})
+	}
+}
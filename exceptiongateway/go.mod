@@ -0,0 +1,24 @@
+module github.com/tae2089/exception/exceptiongateway
+
+go 1.24.5
+
+require (
+	github.com/grpc-ecosystem/grpc-gateway/v2 v2.28.0
+	github.com/tae2089/exception v0.1.0
+	github.com/tae2089/exception/exceptiongrpc v0.1.0
+	google.golang.org/grpc v1.80.0
+)
+
+require (
+	golang.org/x/net v0.49.0 // indirect
+	golang.org/x/sys v0.40.0 // indirect
+	golang.org/x/text v0.34.0 // indirect
+	google.golang.org/genproto/googleapis/api v0.0.0-20260209200024-4cfbd4190f57 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260209200024-4cfbd4190f57 // indirect
+	google.golang.org/protobuf v1.36.11 // indirect
+)
+
+replace (
+	github.com/tae2089/exception => ../
+	github.com/tae2089/exception/exceptiongrpc => ../exceptiongrpc
+)
@@ -0,0 +1,152 @@
+// Package exceptiongcp formats exception.CustomErrors as Google Cloud Error
+// Reporting events. Written as JSON log lines, for example to the standard output
+// of a GKE container, they are picked up by Cloud Logging and grouped in Error
+// Reporting without a client library.
+package exceptiongcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tae2089/exception"
+)
+
+// EventType marks a log entry as an Error Reporting event.
+const EventType = "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent"
+
+// ServiceContext identifies the service that reported an error.
+type ServiceContext struct {
+	Service string `json:"service"`
+	Version string `json:"version,omitempty"`
+}
+
+// ReportLocation is where an error was reported, used when its message has no
+// stack trace.
+type ReportLocation struct {
+	FilePath     string `json:"filePath"`
+	LineNumber   int    `json:"lineNumber"`
+	FunctionName string `json:"functionName"`
+}
+
+// Context holds the location of an error.
+type Context struct {
+	ReportLocation *ReportLocation `json:"reportLocation,omitempty"`
+}
+
+// Event is an error event in the structure Error Reporting reads from log
+// entries.
+type Event struct {
+	Type           string            `json:"@type"`
+	EventTime      time.Time         `json:"eventTime,omitzero"`
+	Severity       string            `json:"severity"`
+	ServiceContext ServiceContext    `json:"serviceContext"`
+	Message        string            `json:"message"`
+	Context        *Context          `json:"context,omitempty"`
+	Labels         map[string]string `json:"logging.googleapis.com/labels,omitempty"`
+}
+
+// Format converts the first CustomError in err's chain to an event. The message
+// is the error message followed by its trace in the goroutine stack format Error
+// Reporting parses, starting from the origin; the report location is the origin
+// frame. The error ID, code and fields are attached as labels.
+func Format(err error, service ServiceContext) Event {
+	var customErr *exception.CustomError
+	if !errors.As(err, &customErr) || customErr == nil {
+		var msg string
+		if err != nil {
+			msg = err.Error()
+		}
+		customErr = exception.New(msg, exception.ErrorInternalServer, exception.WithCause(err))
+	}
+	event := Event{
+		Type:           EventType,
+		EventTime:      customErr.CreatedAt(),
+		Severity:       severity(customErr.Severity()),
+		ServiceContext: service,
+		Message:        customErr.Error(),
+		Labels:         map[string]string{"error_code": fmt.Sprint(int(customErr.Code()))},
+	}
+	if id := customErr.ID(); id != "" {
+		event.Labels["error_id"] = id
+	}
+	for k, v := range customErr.Fields() {
+		event.Labels[k] = fmt.Sprint(v)
+	}
+	if stack := stack(customErr); stack != "" {
+		event.Message += "\n\n" + stack
+	}
+	if origin := customErr.Origin(); origin.File != "" {
+		event.Context = &Context{ReportLocation: &ReportLocation{
+			FilePath:     origin.File,
+			LineNumber:   origin.Line,
+			FunctionName: origin.Function,
+		}}
+	}
+	return event
+}
+
+// stack formats the capture points of e from the origin to the latest wrap as a
+// goroutine stack, innermost frame first.
+func stack(e *exception.CustomError) string {
+	points := e.TracePoints()
+	if len(points) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("goroutine 1 [running]:\n")
+	for i := len(points) - 1; i >= 0; i-- {
+		for _, frame := range points[i].Frames {
+			if frame.File == "" {
+				continue
+			}
+			fmt.Fprintf(&sb, "%s(...)\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		}
+	}
+	return sb.String()
+}
+
+func severity(s exception.Severity) string {
+	switch s {
+	case exception.SeverityDebug:
+		return "DEBUG"
+	case exception.SeverityInfo:
+		return "INFO"
+	case exception.SeverityWarning:
+		return "WARNING"
+	case exception.SeverityCritical:
+		return "CRITICAL"
+	}
+	return "ERROR"
+}
+
+// Write writes err to w as a single-line JSON event; see Format.
+func Write(w io.Writer, err error, service ServiceContext) error {
+	data, marshalErr := json.Marshal(Format(err, service))
+	if marshalErr != nil {
+		return marshalErr
+	}
+	_, writeErr := w.Write(append(data, '\n'))
+	return writeErr
+}
+
+// Sink returns an exception.Sink writing every reported error to w, such as
+// os.Stdout, with Write. Writes are serialized so lines do not interleave.
+func Sink(w io.Writer, service ServiceContext) exception.Sink {
+	var mu sync.Mutex
+	return exception.SinkFunc(func(ctx context.Context, errs []*exception.CustomError) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, e := range errs {
+			if err := Write(w, e, service); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,109 @@
+package exceptiongcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/tae2089/exception"
+)
+
+func TestFormat(t *testing.T) {
+	var typedNil *exception.CustomError
+	tests := []struct {
+		name     string
+		err      error
+		message  string
+		severity string
+		labels   map[string]string
+		located  bool
+	}{
+		{"wrapped error", exception.Wrap(io.EOF, exception.WithCode(exception.ErrorNotFound), exception.WithMessage("user missing"), exception.WithField("user", 7)),
+			"user missing\n\ngoroutine 1 [running]:\n", "WARNING", map[string]string{"error_code": "404", "user": "7"}, true},
+		{"no trace", exception.New("boom", exception.ErrorInternalServer, exception.WithSeverity(exception.SeverityCritical)),
+			"boom", "CRITICAL", map[string]string{"error_code": "500"}, false},
+		{"plain error", io.EOF, "EOF", "ERROR", map[string]string{"error_code": "500"}, false},
+		{"nil", nil, "internal server error", "ERROR", map[string]string{"error_code": "500"}, false},
+		{"typed nil", typedNil, "<nil>", "ERROR", map[string]string{"error_code": "500"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := Format(tt.err, ServiceContext{Service: "users", Version: "1.2.0"})
+			if event.Type != EventType || event.Severity != tt.severity || event.ServiceContext.Service != "users" || event.EventTime.IsZero() {
+				t.Fatalf("event = %+v", event)
+			}
+			if !strings.HasPrefix(event.Message, tt.message) || !tt.located && event.Message != tt.message {
+				t.Fatalf("message = %q, want %q", event.Message, tt.message)
+			}
+			for k, v := range tt.labels {
+				if event.Labels[k] != v {
+					t.Fatalf("labels = %v, want %v", event.Labels, tt.labels)
+				}
+			}
+			if event.Labels["error_id"] == "" {
+				t.Fatalf("labels = %v, want an error_id", event.Labels)
+			}
+			// 추적이 있으면 원점 프레임이 보고 위치
+			if located := event.Context != nil; located != tt.located ||
+				located && !strings.HasSuffix(event.Context.ReportLocation.FilePath, "gcp_test.go") {
+				t.Fatalf("context = %+v", event.Context)
+			}
+		})
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		severity exception.Severity
+		want     string
+	}{
+		{exception.SeverityDebug, "DEBUG"},
+		{exception.SeverityInfo, "INFO"},
+		{exception.SeverityWarning, "WARNING"},
+		{exception.SeverityError, "ERROR"},
+		{exception.SeverityCritical, "CRITICAL"},
+		{0, "ERROR"},
+	}
+	for _, tt := range tests {
+		if got := severity(tt.severity); got != tt.want {
+			t.Errorf("severity(%s) = %q, want %q", tt.severity, got, tt.want)
+		}
+	}
+}
+
+func TestSink(t *testing.T) {
+	tests := []struct {
+		name string
+		errs []*exception.CustomError
+	}{
+		{"none", nil},
+		{"batch", []*exception.CustomError{exception.New("a", exception.ErrorNotFound), exception.New("b", exception.ErrorConflict)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Sink(&buf, ServiceContext{Service: "users"}).Send(context.Background(), tt.errs); err != nil {
+				t.Fatal(err)
+			}
+			// 에러마다 JSON 한 줄
+			scanner := bufio.NewScanner(&buf)
+			var i int
+			for ; scanner.Scan(); i++ {
+				var line map[string]any
+				if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+					t.Fatal(err)
+				}
+				if line["@type"] != EventType || line["message"] != tt.errs[i].Error() {
+					t.Fatalf("line %d = %v", i, line)
+				}
+			}
+			if i != len(tt.errs) {
+				t.Fatalf("wrote %d lines, want %d", i, len(tt.errs))
+			}
+		})
+	}
+}
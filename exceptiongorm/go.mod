@@ -0,0 +1,16 @@
+module github.com/tae2089/exception/exceptiongorm
+
+go 1.24.5
+
+require (
+	github.com/tae2089/exception v0.1.0
+	gorm.io/gorm v1.31.2
+)
+
+require (
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	golang.org/x/text v0.20.0 // indirect
+)
+
+replace github.com/tae2089/exception => ../
@@ -0,0 +1,176 @@
+// Package exceptiongorm translates GORM errors into coded exception.CustomErrors
+// at the repository layer: missing records become 404, duplicate keys and foreign
+// key violations 409, and other constraint violations 400, with the table and
+// operation attached as fields. Constraint violations are recognized from GORM's
+// translated errors, from the SQLSTATE or SQL Server error number of the driver
+// error, and from the messages of the MySQL and SQLite drivers, so no driver
+// package is imported.
+package exceptiongorm
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/tae2089/exception"
+	"gorm.io/gorm"
+)
+
+// Field keys attached to translated errors.
+const (
+	FieldTable     = "db.table"
+	FieldOperation = "db.operation"
+)
+
+// Operations reported under FieldOperation.
+const (
+	OpCreate = "create"
+	OpQuery  = "query"
+	OpUpdate = "update"
+	OpDelete = "delete"
+	OpRow    = "row"
+	OpRaw    = "raw"
+)
+
+// Code returns the ErrorCode for a GORM or driver error reported by the named
+// dialect, such as "postgres", "mysql", "sqlite" or "sqlserver", and false when
+// err is not a recognized database error.
+func Code(err error, dialect string) (exception.ErrorCode, bool) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return exception.ErrorNotFound, true
+	case errors.Is(err, gorm.ErrDuplicatedKey), errors.Is(err, gorm.ErrForeignKeyViolated):
+		return exception.ErrorConflict, true
+	case errors.Is(err, gorm.ErrCheckConstraintViolated), errors.Is(err, gorm.ErrInvalidData),
+		errors.Is(err, gorm.ErrInvalidField), errors.Is(err, gorm.ErrInvalidValue):
+		return exception.ErrorBadRequest, true
+	}
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		if code, ok := codeFromSQLState(stateErr.SQLState()); ok {
+			return code, true
+		}
+	}
+	var numberErr interface{ SQLErrorNumber() int32 }
+	if errors.As(err, &numberErr) {
+		switch numberErr.SQLErrorNumber() {
+		case 2601, 2627, 547:
+			return exception.ErrorConflict, true
+		case 515, 8152:
+			return exception.ErrorBadRequest, true
+		}
+	}
+	return codeFromMessage(err.Error(), dialect)
+}
+
+// codeFromSQLState maps integrity constraint (class 23) and data exception
+// (class 22) SQLSTATEs, as reported by pgx and lib/pq.
+func codeFromSQLState(state string) (exception.ErrorCode, bool) {
+	switch {
+	case state == "23505", state == "23503":
+		return exception.ErrorConflict, true
+	case strings.HasPrefix(state, "23"), strings.HasPrefix(state, "22"):
+		return exception.ErrorBadRequest, true
+	}
+	return 0, false
+}
+
+// codeFromMessage recognizes constraint violations of the MySQL and SQLite
+// drivers, which expose no SQLSTATE accessor, by their messages.
+func codeFromMessage(msg, dialect string) (exception.ErrorCode, bool) {
+	var conflicts, invalid []string
+	switch dialect {
+	case "mysql":
+		conflicts = []string{"Error 1062", "Error 1451", "Error 1452"}
+		invalid = []string{"Error 1048", "Error 1364", "Error 1406", "Error 3819"}
+	case "sqlite":
+		conflicts = []string{"UNIQUE constraint failed", "FOREIGN KEY constraint failed"}
+		invalid = []string{"NOT NULL constraint failed", "CHECK constraint failed"}
+	default:
+		return 0, false
+	}
+	for _, s := range conflicts {
+		if strings.Contains(msg, s) {
+			return exception.ErrorConflict, true
+		}
+	}
+	for _, s := range invalid {
+		if strings.Contains(msg, s) {
+			return exception.ErrorBadRequest, true
+		}
+	}
+	return 0, false
+}
+
+// Translate converts the error of db to a CustomError with the code from Code and
+// the statement's table and op as fields, capturing the caller's trace.
+// Unrecognized errors become internal server errors, while unrecognized
+// CustomErrors keep their code. It returns nil when db has no error.
+func Translate(db *gorm.DB, op string) error {
+	if db == nil || db.Error == nil {
+		return nil
+	}
+	return exception.Wrap(db.Error, append(translateOptions(db, op), exception.WithCallerSkip(1))...)
+}
+
+func translateOptions(db *gorm.DB, op string) []exception.CustomErrorOption {
+	var dialect, table string
+	if db.Dialector != nil {
+		dialect = db.Dialector.Name()
+	}
+	if db.Statement != nil {
+		table = db.Statement.Table
+	}
+	opts := []exception.CustomErrorOption{exception.WithField(FieldOperation, op)}
+	if table != "" {
+		opts = append(opts, exception.WithField(FieldTable, table))
+	}
+	code, ok := Code(db.Error, dialect)
+	if _, isCustom := db.Error.(*exception.CustomError); !isCustom {
+		opts = append(opts, exception.WithMessage(db.Error.Error()))
+		if !ok {
+			code, ok = exception.ErrorInternalServer, true
+		}
+	}
+	// CustomError는 인식되지 않으면 기존 코드를 유지
+	if ok {
+		opts = append(opts, exception.WithCode(code))
+	}
+	return opts
+}
+
+// Plugin is a GORM plugin translating the error of every operation like Translate.
+// The plugin records no trace, as it runs inside GORM's callbacks; call Translate
+// or exception.Wrap at the repository layer to capture one.
+//
+//	db.Use(exceptiongorm.Plugin{})
+type Plugin struct{}
+
+func (Plugin) Name() string {
+	return "exception"
+}
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	processors := []struct {
+		op       string
+		register func(name string, fn func(*gorm.DB)) error
+	}{
+		{OpCreate, callback.Create().After("*").Register},
+		{OpQuery, callback.Query().After("*").Register},
+		{OpUpdate, callback.Update().After("*").Register},
+		{OpDelete, callback.Delete().After("*").Register},
+		{OpRow, callback.Row().After("*").Register},
+		{OpRaw, callback.Raw().After("*").Register},
+	}
+	for _, p := range processors {
+		op := p.op
+		if err := p.register("exception:translate", func(tx *gorm.DB) {
+			if tx.Error != nil {
+				tx.Error = exception.Wrap(tx.Error, append(translateOptions(tx, op), exception.WithNoTrace())...)
+			}
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package exceptiongorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tae2089/exception"
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// pgError is a driver error exposing its SQLSTATE like pgx and lib/pq.
+type pgError struct {
+	state string
+}
+
+func (e pgError) Error() string    { return "ERROR: constraint violated (SQLSTATE " + e.state + ")" }
+func (e pgError) SQLState() string { return e.state }
+
+// mssqlError is a driver error exposing its number like go-mssqldb.
+type mssqlError struct {
+	number int32
+}
+
+func (e mssqlError) Error() string         { return fmt.Sprintf("mssql: error %d", e.number) }
+func (e mssqlError) SQLErrorNumber() int32 { return e.number }
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		dialect string
+		code    exception.ErrorCode
+		ok      bool
+	}{
+		{"record not found", gorm.ErrRecordNotFound, "postgres", exception.ErrorNotFound, true},
+		{"wrapped record not found", fmt.Errorf("find user: %w", gorm.ErrRecordNotFound), "mysql", exception.ErrorNotFound, true},
+		{"translated duplicate key", gorm.ErrDuplicatedKey, "sqlite", exception.ErrorConflict, true},
+		{"translated foreign key", gorm.ErrForeignKeyViolated, "sqlite", exception.ErrorConflict, true},
+		{"translated check", gorm.ErrCheckConstraintViolated, "postgres", exception.ErrorBadRequest, true},
+		{"postgres unique", pgError{"23505"}, "postgres", exception.ErrorConflict, true},
+		{"postgres foreign key", pgError{"23503"}, "postgres", exception.ErrorConflict, true},
+		{"postgres not null", pgError{"23502"}, "postgres", exception.ErrorBadRequest, true},
+		{"postgres data exception", pgError{"22001"}, "postgres", exception.ErrorBadRequest, true},
+		{"postgres other state", pgError{"40001"}, "postgres", 0, false},
+		{"mysql unique", errors.New("Error 1062 (23000): Duplicate entry 'a' for key 'users.email'"), "mysql", exception.ErrorConflict, true},
+		{"mysql foreign key", errors.New("Error 1452 (23000): Cannot add or update a child row"), "mysql", exception.ErrorConflict, true},
+		{"mysql not null", errors.New("Error 1048 (23000): Column 'name' cannot be null"), "mysql", exception.ErrorBadRequest, true},
+		{"sqlite unique", errors.New("UNIQUE constraint failed: users.email"), "sqlite", exception.ErrorConflict, true},
+		{"sqlite foreign key", errors.New("FOREIGN KEY constraint failed"), "sqlite", exception.ErrorConflict, true},
+		{"sqlite check", errors.New("CHECK constraint failed: age"), "sqlite", exception.ErrorBadRequest, true},
+		{"sqlserver unique", mssqlError{2627}, "sqlserver", exception.ErrorConflict, true},
+		{"sqlserver foreign key", mssqlError{547}, "sqlserver", exception.ErrorConflict, true},
+		{"sqlserver not null", mssqlError{515}, "sqlserver", exception.ErrorBadRequest, true},
+		{"message of another dialect", errors.New("UNIQUE constraint failed: users.email"), "postgres", 0, false},
+		{"unknown", errors.New("connection reset"), "mysql", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, ok := Code(tt.err, tt.dialect)
+			if code != tt.code || ok != tt.ok {
+				t.Fatalf("Code = %d, %v, want %d, %v", code, ok, tt.code, tt.ok)
+			}
+		})
+	}
+}
+
+// fakeDialector is a GORM dialector whose connection fails every statement with
+// err.
+type fakeDialector struct {
+	name string
+	err  error
+}
+
+func (d fakeDialector) Name() string { return d.name }
+
+func (d fakeDialector) Initialize(db *gorm.DB) error {
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{})
+	db.ConnPool = failingPool{err: d.err}
+	return nil
+}
+
+func (fakeDialector) Migrator(*gorm.DB) gorm.Migrator { return nil }
+
+func (fakeDialector) DataTypeOf(*schema.Field) string { return "" }
+
+func (fakeDialector) DefaultValueOf(*schema.Field) clause.Expression { return nil }
+
+func (fakeDialector) BindVarTo(w clause.Writer, _ *gorm.Statement, _ any) { w.WriteByte('?') }
+
+func (fakeDialector) QuoteTo(w clause.Writer, s string) { w.WriteString(`"` + s + `"`) }
+
+func (fakeDialector) Explain(sql string, _ ...any) string { return sql }
+
+// failingPool is a gorm.ConnPool failing every statement with err.
+type failingPool struct {
+	err error
+}
+
+func (p failingPool) PrepareContext(context.Context, string) (*sql.Stmt, error) { return nil, p.err }
+
+func (p failingPool) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	return nil, p.err
+}
+
+func (p failingPool) QueryContext(context.Context, string, ...any) (*sql.Rows, error) {
+	return nil, p.err
+}
+
+func (p failingPool) QueryRowContext(context.Context, string, ...any) *sql.Row { return nil }
+
+type user struct {
+	ID    int
+	Email string
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		err     error
+		code    exception.ErrorCode
+		message string
+	}{
+		{"record not found", "postgres", gorm.ErrRecordNotFound, exception.ErrorNotFound, "record not found"},
+		{"unique violation", "sqlite", errors.New("UNIQUE constraint failed: users.email"), exception.ErrorConflict, "UNIQUE constraint failed: users.email"},
+		{"foreign key violation", "postgres", pgError{"23503"}, exception.ErrorConflict, "ERROR: constraint violated (SQLSTATE 23503)"},
+		{"unknown", "mysql", errors.New("connection reset"), exception.ErrorInternalServer, "connection reset"},
+		{"custom error", "mysql", exception.New("user missing", exception.ErrorNotFound), exception.ErrorNotFound, "user missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &gorm.DB{
+				Config:    &gorm.Config{Dialector: fakeDialector{name: tt.dialect}},
+				Statement: &gorm.Statement{Table: "users"},
+				Error:     tt.err,
+			}
+			var customErr *exception.CustomError
+			if !errors.As(Translate(db, OpQuery), &customErr) {
+				t.Fatalf("Translate = %v", customErr)
+			}
+			if customErr.Code() != tt.code || customErr.Error() != tt.message {
+				t.Fatalf("Translate = %q (%d), want %q (%d)", customErr.Error(), customErr.Code(), tt.message, tt.code)
+			}
+			if fields := customErr.Fields(); fields[FieldTable] != "users" || fields[FieldOperation] != OpQuery {
+				t.Fatalf("fields = %v", fields)
+			}
+			if !errors.Is(customErr, tt.err) {
+				t.Fatal("the driver error should stay reachable")
+			}
+			// 추적은 Translate 내부가 아니라 호출한 곳에서 시작
+			points := customErr.TracePoints()
+			if len(points) == 0 || !strings.HasSuffix(points[0].Frames[0].Function, "exceptiongorm.TestTranslate.func1") {
+				t.Fatalf("trace = %q", customErr.Trace)
+			}
+		})
+	}
+	if Translate(nil, OpQuery) != nil || Translate(&gorm.DB{}, OpQuery) != nil {
+		t.Fatal("Translate should be nil without an error")
+	}
+}
+
+func TestPlugin(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		err     error
+		run     func(db *gorm.DB) error
+		op      string
+		code    exception.ErrorCode
+	}{
+		{"create unique violation", "sqlite", errors.New("UNIQUE constraint failed: users.email"),
+			func(db *gorm.DB) error { return db.Create(&user{Email: "a@example.com"}).Error }, OpCreate, exception.ErrorConflict},
+		{"query foreign key violation", "postgres", pgError{"23503"},
+			func(db *gorm.DB) error { return db.Find(&[]user{}).Error }, OpQuery, exception.ErrorConflict},
+		{"delete not null violation", "mysql", errors.New("Error 1048 (23000): Column 'email' cannot be null"),
+			func(db *gorm.DB) error { return db.Delete(&user{ID: 1}).Error }, OpDelete, exception.ErrorBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := gorm.Open(fakeDialector{name: tt.dialect, err: tt.err}, &gorm.Config{SkipDefaultTransaction: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := db.Use(Plugin{}); err != nil {
+				t.Fatal(err)
+			}
+			var customErr *exception.CustomError
+			if !errors.As(tt.run(db), &customErr) {
+				t.Fatal("the plugin should translate the error")
+			}
+			if customErr.Code() != tt.code || customErr.Fields()[FieldOperation] != tt.op || customErr.Fields()[FieldTable] != "users" {
+				t.Fatalf("error = %q (%d) with fields %v", customErr.Error(), customErr.Code(), customErr.Fields())
+			}
+			if customErr.Trace != "" {
+				t.Fatalf("the plugin should not record GORM's callback frames: %s", customErr.Trace)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package exceptiongrpc
+
+import (
+	"strconv"
+
+	"github.com/tae2089/exception"
+	"google.golang.org/grpc/codes"
+)
+
+// CodeToGRPC maps an ErrorCode to the gRPC status code registered for it in the
+// catalog or, for codes without one, the gRPC status code closest to its HTTP
+// status; see exception.HTTPStatus.
+func CodeToGRPC(code exception.ErrorCode) codes.Code {
+	if info, ok := exception.LookupCode(code); ok && info.GRPCCode != "" {
+		var c codes.Code
+		if err := c.UnmarshalJSON([]byte(strconv.Quote(info.GRPCCode))); err == nil {
+			return c
+		}
+	}
+	if code == 0 {
+		return codes.Unknown
+	}
+	status := exception.HTTPStatus(code)
+	switch status {
+	case 400:
+		return codes.InvalidArgument
+	case 401:
+		return codes.Unauthenticated
+	case 403:
+		return codes.PermissionDenied
+	case 404:
+		return codes.NotFound
+	case 408, 504:
+		return codes.DeadlineExceeded
+	case 409:
+		return codes.AlreadyExists
+	case 412:
+		return codes.FailedPrecondition
+	case 429:
+		return codes.ResourceExhausted
+	case 499:
+		return codes.Canceled
+	case 501:
+		return codes.Unimplemented
+	case 503:
+		return codes.Unavailable
+	}
+	switch {
+	case status >= 400 && status < 500:
+		return codes.InvalidArgument
+	case status >= 500:
+		return codes.Internal
+	}
+	return codes.Unknown
+}
+
+// CodeFromGRPC maps a gRPC status code to an ErrorCode.
+func CodeFromGRPC(code codes.Code) exception.ErrorCode {
+	switch code {
+	case codes.OK:
+		return 0
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return exception.ErrorInvalidRequest
+	case codes.DeadlineExceeded:
+		return 504
+	case codes.NotFound:
+		return exception.ErrorDataNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return 409
+	case codes.PermissionDenied:
+		return 403
+	case codes.Unauthenticated:
+		return exception.ErrorUnAuthorized
+	case codes.ResourceExhausted:
+		return 429
+	case codes.Unimplemented:
+		return 501
+	case codes.Unavailable:
+		return 503
+	}
+	return exception.ErrorInternalServer
+}
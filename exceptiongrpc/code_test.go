@@ -0,0 +1,63 @@
+package exceptiongrpc
+
+import (
+	"testing"
+
+	"github.com/tae2089/exception"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCodeMapping(t *testing.T) {
+	tests := []struct {
+		code exception.ErrorCode
+		grpc codes.Code
+		back exception.ErrorCode
+	}{
+		{0, codes.Unknown, exception.ErrorInternalServer},
+		{400, codes.InvalidArgument, 400},
+		{401, codes.Unauthenticated, 401},
+		{403, codes.PermissionDenied, 403},
+		{404, codes.NotFound, 404},
+		{408, codes.DeadlineExceeded, 504},
+		{409, codes.AlreadyExists, 409},
+		{418, codes.InvalidArgument, 400},
+		{429, codes.ResourceExhausted, 429},
+		{499, codes.Canceled, 499},
+		{500, codes.Internal, 500},
+		{501, codes.Unimplemented, 501},
+		{503, codes.Unavailable, 503},
+		{504, codes.DeadlineExceeded, 504},
+	}
+	for _, tt := range tests {
+		if got := CodeToGRPC(tt.code); got != tt.grpc {
+			t.Fatalf("CodeToGRPC(%d) = %s, want %s", tt.code, got, tt.grpc)
+		}
+		if got := CodeFromGRPC(tt.grpc); got != tt.back {
+			t.Fatalf("CodeFromGRPC(%s) = %d, want %d", tt.grpc, got, tt.back)
+		}
+	}
+	if CodeFromGRPC(codes.OK) != 0 {
+		t.Fatal("CodeFromGRPC(OK) should be 0")
+	}
+}
+
+func TestCodeToGRPCFromCatalog(t *testing.T) {
+	exception.RegisterCode(exception.CodeInfo{Code: 40931, Name: "OrderLocked", GRPCCode: "ABORTED"})
+	exception.RegisterCode(exception.CodeInfo{Code: 40932, Name: "OrderStale", GRPCCode: "Stale"})
+	exception.RegisterCode(exception.CodeInfo{Code: 40401, Name: "OrderMissing", HTTPStatus: 404})
+	tests := []struct {
+		code exception.ErrorCode
+		want codes.Code
+	}{
+		{40931, codes.Aborted},
+		// 알 수 없는 이름은 기본 매핑
+		{40932, codes.Internal},
+		// gRPC 코드가 없으면 HTTP 상태로 매핑
+		{40401, codes.NotFound},
+	}
+	for _, tt := range tests {
+		if got := CodeToGRPC(tt.code); got != tt.want {
+			t.Errorf("CodeToGRPC(%d) = %s, want %s", tt.code, got, tt.want)
+		}
+	}
+}
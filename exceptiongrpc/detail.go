@@ -0,0 +1,89 @@
+package exceptiongrpc
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/tae2089/exception"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// detailKey is the field of the detail struct holding the encoded error.
+const detailKey = "exception"
+
+var exposeChain bool
+
+// SetExposeChain sets whether errors sent to clients carry the whole encoded
+// error chain, including internal messages, fields and traces. By default only
+// the code, ID, domain, public message and hint of the error are sent. Enable it
+// only between trusted services. It should be called during initialization.
+func SetExposeChain(enabled bool) {
+	exposeChain = enabled
+}
+
+// Detail encodes err as a protobuf message that can be attached to RPC errors.
+// It holds the public view of the first CustomError in err's chain, or the whole
+// chain when SetExposeChain is enabled.
+func Detail(err error) (*structpb.Struct, error) {
+	var customErr *exception.CustomError
+	if !errors.As(err, &customErr) || customErr == nil {
+		return nil, errors.New("exceptiongrpc: no CustomError in the error chain")
+	}
+	encoded := map[string]any{
+		"id":             customErr.ID(),
+		"code":           int(customErr.Code()),
+		"domain":         customErr.Domain(),
+		"message":        PublicMessage(err),
+		"public_message": customErr.PublicMessage(),
+		"hint":           customErr.Hint(),
+	}
+	if exposeChain {
+		data, err := exception.Encode(err)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &encoded); err != nil {
+			return nil, err
+		}
+	}
+	return structpb.NewStruct(map[string]any{detailKey: encoded})
+}
+
+// PublicMessage returns the message sent to clients for err: the public message
+// of the first CustomError in the chain, or, when SetExposeChain is enabled, the
+// error message. Without either it falls back to the name of the gRPC code.
+func PublicMessage(err error) string {
+	var customErr *exception.CustomError
+	if !errors.As(err, &customErr) || customErr == nil {
+		if exposeChain && err != nil {
+			return err.Error()
+		}
+		return codes.Unknown.String()
+	}
+	if msg := customErr.PublicMessage(); msg != "" {
+		return msg
+	}
+	if exposeChain {
+		return customErr.Error()
+	}
+	return CodeToGRPC(customErr.Code()).String()
+}
+
+// FromDetail rebuilds the remote error encoded by Detail. It reports false when s
+// was not produced by Detail.
+func FromDetail(s *structpb.Struct) (*exception.CustomError, bool) {
+	v, ok := s.GetFields()[detailKey]
+	if !ok || v.GetStructValue() == nil {
+		return nil, false
+	}
+	data, err := v.GetStructValue().MarshalJSON()
+	if err != nil {
+		return nil, false
+	}
+	customErr, err := exception.Decode(data)
+	if err != nil {
+		return nil, false
+	}
+	return customErr, true
+}
@@ -0,0 +1,92 @@
+package exceptiongrpc
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/tae2089/exception"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func setExposeChain(t *testing.T, enabled bool) {
+	t.Helper()
+	prev := exposeChain
+	SetExposeChain(enabled)
+	t.Cleanup(func() { SetExposeChain(prev) })
+}
+
+func internalError() error {
+	return exception.Wrap(io.EOF, exception.WithCode(exception.ErrorUserNotFound), exception.WithMessage("user 42 missing in db"),
+		exception.WithField("user", "42"), exception.WithID("e-1"), exception.WithDomain("users"), exception.WithHint("check the id"))
+}
+
+func TestDetail(t *testing.T) {
+	tests := []struct {
+		name    string
+		expose  bool
+		err     error
+		message string
+		chain   bool
+	}{
+		{"public view", false, internalError(), "NotFound", false},
+		{"public message", false, exception.Wrap(internalError(), exception.WithPublicMessage("user not found")), "user not found", false},
+		{"whole chain", true, internalError(), "user 42 missing in db", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setExposeChain(t, tt.expose)
+			detail, err := Detail(tt.err)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := FromDetail(detail)
+			if !ok {
+				t.Fatal("FromDetail should accept a Detail")
+			}
+			if got.Code() != exception.ErrorUserNotFound || got.ID() != "e-1" || got.Domain() != "users" || got.Hint() != "check the id" || !got.IsRemote() {
+				t.Fatalf("FromDetail = %+v", got)
+			}
+			if got.Error() != tt.message {
+				t.Fatalf("message = %q, want %q", got.Error(), tt.message)
+			}
+			_, hasField := got.Field("user")
+			if chain := got.Trace != "" && got.Unwrap() != nil && hasField; chain != tt.chain {
+				t.Fatalf("chain sent = %v, want %v: %+v", chain, tt.chain, got)
+			}
+		})
+	}
+}
+
+func TestDetailWithoutCustomError(t *testing.T) {
+	if _, err := Detail(errors.New("boom")); err == nil {
+		t.Fatal("Detail should fail without a CustomError")
+	}
+	other, _ := structpb.NewStruct(map[string]any{"other": "value"})
+	if _, ok := FromDetail(other); ok {
+		t.Fatal("FromDetail should reject structs not produced by Detail")
+	}
+}
+
+func TestPublicMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		expose bool
+		err    error
+		want   string
+	}{
+		{"code name", false, internalError(), "NotFound"},
+		{"public message", false, exception.New("db down", exception.ErrorInternalServer, exception.WithPublicMessage("try again")), "try again"},
+		{"plain error", false, errors.New("db: boom"), "Unknown"},
+		{"exposed message", true, internalError(), "user 42 missing in db"},
+		{"exposed plain error", true, errors.New("db: boom"), "db: boom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setExposeChain(t, tt.expose)
+			if got := PublicMessage(tt.err); got != tt.want {
+				t.Fatalf("PublicMessage = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
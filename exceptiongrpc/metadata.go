@@ -0,0 +1,65 @@
+// Package exceptiongrpc integrates exception.CustomError with gRPC.
+package exceptiongrpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tae2089/exception"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Metadata keys used to correlate errors across service hops.
+var (
+	MetadataErrorID     = strings.ToLower(exception.HeaderErrorID)
+	MetadataErrorCode   = strings.ToLower(exception.HeaderErrorCode)
+	MetadataErrorDomain = strings.ToLower(exception.HeaderErrorDomain)
+)
+
+// Metadata returns the ID, code and domain of the first CustomError in err's chain
+// as gRPC metadata.
+func Metadata(err error) metadata.MD {
+	h := make(map[string][]string)
+	exception.SetHTTPHeaders(h, err)
+	md := metadata.MD{}
+	for k, v := range h {
+		md.Set(strings.ToLower(k), v...)
+	}
+	return md
+}
+
+// FromMetadata rebuilds the remote error described by md. It returns nil when md
+// carries no error metadata.
+func FromMetadata(md metadata.MD) *exception.CustomError {
+	return exception.FromPropagation(first(md, MetadataErrorID), first(md, MetadataErrorCode), first(md, MetadataErrorDomain))
+}
+
+// SetTrailer sends the error metadata of err as trailers of the current server call.
+func SetTrailer(ctx context.Context, err error) error {
+	md := Metadata(err)
+	if md.Len() == 0 {
+		return nil
+	}
+	return grpc.SetTrailer(ctx, md)
+}
+
+func first(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// UnaryServerInterceptor converts errors returned by handlers with ToStatus and
+// sends their error metadata as trailers.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			_ = SetTrailer(ctx, err)
+			return resp, ToStatus(err).Err()
+		}
+		return resp, nil
+	}
+}
@@ -0,0 +1,89 @@
+package exceptiongrpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tae2089/exception"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestMetadataRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want metadata.MD
+	}{
+		{
+			name: "full",
+			err:  exception.Wrap(exception.New("boom", exception.ErrorUserNotFound), exception.WithID("e-1"), exception.WithDomain("users")),
+			want: metadata.Pairs(MetadataErrorID, "e-1", MetadataErrorCode, "404", MetadataErrorDomain, "users"),
+		},
+		{name: "plain error", err: errors.New("boom"), want: metadata.MD{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := Metadata(tt.err)
+			if md.Len() != tt.want.Len() {
+				t.Fatalf("Metadata = %v, want %v", md, tt.want)
+			}
+			for k := range tt.want {
+				if first(md, k) != first(tt.want, k) {
+					t.Fatalf("%s = %v, want %v", k, md.Get(k), tt.want.Get(k))
+				}
+			}
+			remote := FromMetadata(md)
+			if tt.want.Len() == 0 {
+				if remote != nil {
+					t.Fatalf("FromMetadata = %+v, want nil", remote)
+				}
+				return
+			}
+			if !remote.IsRemote() || remote.ID() != "e-1" || remote.Code() != exception.ErrorUserNotFound || remote.Domain() != "users" {
+				t.Fatalf("FromMetadata = %+v", remote)
+			}
+		})
+	}
+}
+
+type trailerStream struct {
+	grpc.ServerTransportStream
+	trailer metadata.MD
+}
+
+func (s *trailerStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+func TestUnaryServerInterceptorSetsTrailer(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		trailer int
+		code    codes.Code
+	}{
+		{"custom error", exception.Wrap(exception.New("boom", exception.ErrorUserNotFound), exception.WithID("e-1")), 2, codes.NotFound},
+		{"plain error", errors.New("boom"), 0, codes.Unknown},
+		{"status error", status.Error(codes.Unavailable, "down"), 0, codes.Unavailable},
+		{"no error", nil, 0, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &trailerStream{}
+			ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+			handler := func(ctx context.Context, req any) (any, error) { return "ok", tt.err }
+			_, err := UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}, handler)
+			if (err == nil) != (tt.err == nil) || status.Code(err) != tt.code {
+				t.Fatalf("err = %v, want a status with code %s", err, tt.code)
+			}
+			if stream.trailer.Len() != tt.trailer {
+				t.Fatalf("trailer = %v, want %d keys", stream.trailer, tt.trailer)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package exceptiongrpc
+
+import (
+	"errors"
+
+	"github.com/tae2089/exception"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ToStatus converts err to a gRPC status whose code is derived from the first
+// CustomError in the chain and whose message and details hold what Detail
+// exposes. Errors that already carry a status are returned as is.
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+	var customErr *exception.CustomError
+	if !errors.As(err, &customErr) || customErr == nil {
+		if _, ok := status.FromError(err); ok {
+			return status.Convert(err)
+		}
+		return status.New(codes.Unknown, PublicMessage(err))
+	}
+	st := status.New(CodeToGRPC(customErr.Code()), PublicMessage(err))
+	detail, derr := Detail(err)
+	if derr != nil {
+		return st
+	}
+	if withDetail, derr := st.WithDetails(detail); derr == nil {
+		return withDetail
+	}
+	return st
+}
+
+// FromStatus rebuilds the remote CustomError carried by a gRPC status. It reports
+// false when st has no detail produced by Detail.
+func FromStatus(st *status.Status) (*exception.CustomError, bool) {
+	for _, d := range st.Details() {
+		if s, ok := d.(*structpb.Struct); ok {
+			if customErr, ok := FromDetail(s); ok {
+				return customErr, true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,82 @@
+package exceptiongrpc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tae2089/exception"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		expose  bool
+		err     error
+		code    codes.Code
+		message string
+		detail  bool
+	}{
+		{"nil", false, nil, codes.OK, "", false},
+		{"public view", false, internalError(), codes.NotFound, "NotFound", true},
+		{"public message", false, exception.Wrap(internalError(), exception.WithPublicMessage("user not found")), codes.NotFound, "user not found", true},
+		{"whole chain", true, internalError(), codes.NotFound, "user 42 missing in db", true},
+		{"plain error", false, errors.New("db: boom"), codes.Unknown, "Unknown", false},
+		{"exposed plain error", true, errors.New("db: boom"), codes.Unknown, "db: boom", false},
+		{"status error", false, status.Error(codes.Unavailable, "down"), codes.Unavailable, "down", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setExposeChain(t, tt.expose)
+			st := ToStatus(tt.err)
+			if st.Code() != tt.code || st.Message() != tt.message {
+				t.Fatalf("ToStatus = %s %q, want %s %q", st.Code(), st.Message(), tt.code, tt.message)
+			}
+			if got := len(st.Details()) == 1; got != tt.detail {
+				t.Fatalf("details = %v, want detail %v", st.Details(), tt.detail)
+			}
+		})
+	}
+}
+
+func TestStatusRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		expose  bool
+		message string
+		field   bool
+	}{
+		{"public view", false, "NotFound", false},
+		{"whole chain", true, "user 42 missing in db", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setExposeChain(t, tt.expose)
+			// 전송 과정을 거친 상태를 흉내냄
+			st, ok := status.FromError(ToStatus(internalError()).Err())
+			if !ok {
+				t.Fatal("ToStatus should produce a status error")
+			}
+			got, ok := FromStatus(st)
+			if !ok {
+				t.Fatal("FromStatus should find the detail")
+			}
+			if !got.IsRemote() || got.Code() != exception.ErrorUserNotFound || got.ID() != "e-1" || got.Domain() != "users" {
+				t.Fatalf("FromStatus = %+v", got)
+			}
+			if got.Error() != tt.message {
+				t.Fatalf("message = %q, want %q", got.Error(), tt.message)
+			}
+			if _, ok := got.Field("user"); ok != tt.field {
+				t.Fatalf("field sent = %v, want %v", ok, tt.field)
+			}
+		})
+	}
+}
+
+func TestFromStatusWithoutDetail(t *testing.T) {
+	if got, ok := FromStatus(status.New(codes.NotFound, "missing")); ok {
+		t.Fatalf("FromStatus = %+v, want false", got)
+	}
+}
@@ -0,0 +1,78 @@
+// Package exceptionk8s reports exception.CustomErrors as typed conditions on
+// Kubernetes objects.
+package exceptionk8s
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/tae2089/exception"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition reasons used when there is no error, and when the code name of the
+// error has no character allowed in a reason.
+const (
+	ReasonSucceeded = "Succeeded"
+	ReasonError     = "Error"
+)
+
+// maxReasonLength is the maximum length of a condition reason.
+const maxReasonLength = 1024
+
+// Condition returns a condition of condType describing err: status False with the
+// code name as reason and the public message, or the message, when err is not
+// nil, and status True otherwise. Characters not allowed in a reason are removed
+// from the code name.
+func Condition(condType string, generation int64, err error) metav1.Condition {
+	cond := metav1.Condition{
+		Type:               condType,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             ReasonSucceeded,
+	}
+	if err == nil {
+		return cond
+	}
+	cond.Status = metav1.ConditionFalse
+	cond.Reason = reason(exception.ErrorInternalServer.Name())
+	cond.Message = err.Error()
+	var customErr *exception.CustomError
+	if errors.As(err, &customErr) && customErr != nil {
+		cond.Reason = reason(customErr.Code().Name())
+		if msg := customErr.PublicMessage(); msg != "" {
+			cond.Message = msg
+		}
+	}
+	return cond
+}
+
+// SetCondition sets the condition describing err on conditions, typically the
+// Status.Conditions of an object, and reports whether it changed.
+func SetCondition(conditions *[]metav1.Condition, condType string, generation int64, err error) bool {
+	return apimeta.SetStatusCondition(conditions, Condition(condType, generation, err))
+}
+
+// reason turns name into a valid condition reason, matching
+// ^[A-Za-z]([A-Za-z0-9_,:]*[A-Za-z0-9_])?$, or ReasonError if nothing is left.
+func reason(name string) string {
+	b := make([]byte, 0, len(name))
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z':
+		case len(b) == 0:
+			continue // 첫 글자는 영문자여야 함
+		case '0' <= c && c <= '9', c == '_', c == ',', c == ':':
+		default:
+			continue
+		}
+		b = append(b, c)
+	}
+	reason := strings.TrimRight(string(b[:min(len(b), maxReasonLength)]), ",:")
+	if reason == "" {
+		return ReasonError
+	}
+	return reason
+}
@@ -0,0 +1,81 @@
+package exceptionk8s
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/tae2089/exception"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCondition(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		status  metav1.ConditionStatus
+		reason  string
+		message string
+	}{
+		{"nil", nil, metav1.ConditionTrue, ReasonSucceeded, ""},
+		{"plain error", io.EOF, metav1.ConditionFalse, "InternalServerError", "EOF"},
+		{"custom error", exception.NotFound("secret missing"), metav1.ConditionFalse, "NotFound", "secret missing"},
+		{"public message", exception.New("secret db/creds missing", exception.ErrorNotFound, exception.WithPublicMessage("credentials missing")), metav1.ConditionFalse, "NotFound", "credentials missing"},
+		{"in the chain", fmt.Errorf("reconcile: %w", exception.Forbidden("no access")), metav1.ConditionFalse, "Forbidden", "reconcile: no access"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cond := Condition("Ready", 3, tt.err)
+			if cond.Type != "Ready" || cond.ObservedGeneration != 3 {
+				t.Fatalf("Condition = %+v", cond)
+			}
+			if cond.Status != tt.status || cond.Reason != tt.reason || cond.Message != tt.message {
+				t.Fatalf("Condition = %s %s %q, want %s %s %q", cond.Status, cond.Reason, cond.Message, tt.status, tt.reason, tt.message)
+			}
+		})
+	}
+}
+
+func TestConditionReason(t *testing.T) {
+	tests := []struct {
+		name   string
+		reason string
+	}{
+		{"OrderLocked", "OrderLocked"},
+		{"order locked", "orderlocked"},
+		{"order-locked: v2", "orderlocked:v2"},
+		{"42 order", "order"},
+		{"locked,", "locked"},
+		{"-404-", ReasonError},
+		{"주문 잠김", ReasonError},
+	}
+	for i, tt := range tests {
+		code := exception.ErrorCode(42201 + i)
+		exception.RegisterCode(exception.CodeInfo{Code: code, Name: tt.name})
+		// 등록된 이름에서 허용되지 않는 문자는 제거
+		if cond := Condition("Ready", 1, exception.New("invalid", code)); cond.Reason != tt.reason {
+			t.Errorf("reason for %q = %q, want %q", tt.name, cond.Reason, tt.reason)
+		}
+	}
+}
+
+func TestSetCondition(t *testing.T) {
+	var conditions []metav1.Condition
+	steps := []struct {
+		err     error
+		changed bool
+		status  metav1.ConditionStatus
+	}{
+		{exception.NotFound("secret missing"), true, metav1.ConditionFalse},
+		{exception.NotFound("secret missing"), false, metav1.ConditionFalse},
+		{nil, true, metav1.ConditionTrue},
+	}
+	for i, step := range steps {
+		if changed := SetCondition(&conditions, "Ready", 1, step.err); changed != step.changed {
+			t.Fatalf("step %d: changed = %v, want %v", i, changed, step.changed)
+		}
+		if len(conditions) != 1 || conditions[0].Status != step.status {
+			t.Fatalf("step %d: conditions = %+v", i, conditions)
+		}
+	}
+}
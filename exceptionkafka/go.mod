@@ -0,0 +1,7 @@
+module github.com/tae2089/exception/exceptionkafka
+
+go 1.24.5
+
+require github.com/tae2089/exception v0.1.0
+
+replace github.com/tae2089/exception => ../
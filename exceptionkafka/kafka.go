@@ -0,0 +1,100 @@
+// Package exceptionkafka wraps Kafka consumer handler errors with message metadata
+// and classifies them for poison-message handling. It works with any client
+// library, such as sarama or franz-go, through a function describing its message type.
+package exceptionkafka
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tae2089/exception"
+)
+
+// Field keys attached to wrapped handler errors.
+const (
+	FieldTopic     = "kafka.topic"
+	FieldPartition = "kafka.partition"
+	FieldOffset    = "kafka.offset"
+	FieldKey       = "kafka.key"
+)
+
+// Message describes the consumed message an error belongs to.
+type Message struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       []byte
+}
+
+// Disposition tells a consumer what to do with a message after its handler returned.
+type Disposition int
+
+const (
+	// Commit marks the message as processed.
+	Commit Disposition = iota
+	// Retry redelivers the message later because the failure is transient.
+	Retry
+	// DeadLetter moves the message aside because processing it again would fail again.
+	DeadLetter
+)
+
+func (d Disposition) String() string {
+	switch d {
+	case Commit:
+		return "commit"
+	case Retry:
+		return "retry"
+	case DeadLetter:
+		return "dead-letter"
+	}
+	return "unknown"
+}
+
+// Classify returns the disposition for a handler error: nil errors are committed,
+// retryable errors (see exception.IsRetryable) and context errors are retried, as
+// are errors without a code, such as plain errors wrapped by Wrap, since their
+// cause is unknown. Everything else is dead-lettered.
+func Classify(err error) Disposition {
+	if err == nil {
+		return Commit
+	}
+	var customErr *exception.CustomError
+	if !errors.As(err, &customErr) || customErr == nil || customErr.Code() == 0 {
+		return Retry
+	}
+	if exception.IsRetryable(err) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return Retry
+	}
+	return DeadLetter
+}
+
+// Wrap attaches the message's topic, partition, offset and key to err as fields.
+// It returns nil when err is nil.
+func Wrap(err error, msg Message) error {
+	return exception.Wrap(err,
+		exception.WithField(FieldTopic, msg.Topic),
+		exception.WithField(FieldPartition, msg.Partition),
+		exception.WithField(FieldOffset, msg.Offset),
+		exception.WithField(FieldKey, string(msg.Key)),
+		exception.WithCallerSkip(1),
+	)
+}
+
+// HandlerFunc handles a single consumed message of a client library's message type.
+type HandlerFunc[M any] func(ctx context.Context, msg M) error
+
+// Middleware wraps errors returned by next with the metadata of the message,
+// obtained through describe.
+//
+//	handle := exceptionkafka.Middleware(func(m *sarama.ConsumerMessage) exceptionkafka.Message {
+//		return exceptionkafka.Message{Topic: m.Topic, Partition: m.Partition, Offset: m.Offset, Key: m.Key}
+//	}, process)
+func Middleware[M any](describe func(M) Message, next HandlerFunc[M]) HandlerFunc[M] {
+	return func(ctx context.Context, msg M) error {
+		err := next(ctx, msg)
+		if err == nil {
+			return nil
+		}
+		return Wrap(err, describe(msg))
+	}
+}
@@ -0,0 +1,111 @@
+package exceptionkafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/tae2089/exception"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Disposition
+	}{
+		{"nil", nil, Commit},
+		{"plain error", io.EOF, Retry},
+		{"wrapped plain error", Wrap(io.EOF, Message{Topic: "users"}), Retry},
+		{"code 0", exception.New("boom", 0), Retry},
+		{"retryable code", exception.New("upstream down", exception.ErrorCode(503)), Retry},
+		{"explicitly retryable", exception.New("bad input", exception.ErrorDataInvalid, exception.WithRetryable(true)), Retry},
+		{"context canceled", exception.Wrap(context.Canceled, exception.WithCode(exception.ErrorInternalServer)), Retry},
+		{"deadline exceeded", exception.Wrap(context.DeadlineExceeded, exception.WithCode(exception.ErrorInternalServer)), Retry},
+		{"client error", exception.New("bad input", exception.ErrorDataInvalid), DeadLetter},
+		{"wrapped client error", Wrap(exception.New("bad input", exception.ErrorDataInvalid), Message{Topic: "users"}), DeadLetter},
+		{"server error", exception.New("db down", exception.ErrorInternalServer), DeadLetter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.want {
+				t.Fatalf("Classify = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDispositionString(t *testing.T) {
+	tests := []struct {
+		d    Disposition
+		want string
+	}{
+		{Commit, "commit"},
+		{Retry, "retry"},
+		{DeadLetter, "dead-letter"},
+		{Disposition(9), "unknown"},
+	}
+	for _, tt := range tests {
+		if got := tt.d.String(); got != tt.want {
+			t.Errorf("Disposition(%d).String() = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+type consumerMessage struct {
+	topic     string
+	partition int32
+	offset    int64
+	key       string
+}
+
+func describe(m consumerMessage) Message {
+	return Message{Topic: m.topic, Partition: m.partition, Offset: m.offset, Key: []byte(m.key)}
+}
+
+func TestMiddleware(t *testing.T) {
+	msg := consumerMessage{topic: "users", partition: 3, offset: 42, key: "u-7"}
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"no error", nil},
+		{"plain error", io.EOF},
+		{"custom error", exception.New("bad input", exception.ErrorDataInvalid)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handle := Middleware(describe, func(ctx context.Context, m consumerMessage) error { return tt.err })
+			err := handle(context.Background(), msg)
+			if tt.err == nil {
+				if err != nil {
+					t.Fatalf("err = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want it to wrap %v", err, tt.err)
+			}
+			var customErr *exception.CustomError
+			if !errors.As(err, &customErr) {
+				t.Fatalf("err = %T, want a CustomError", err)
+			}
+			want := map[string]any{FieldTopic: "users", FieldPartition: int32(3), FieldOffset: int64(42), FieldKey: "u-7"}
+			if got := customErr.Fields(); fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Fatalf("fields = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestWrapRecordsCaller(t *testing.T) {
+	customErr := Wrap(io.EOF, Message{Topic: "users"}).(*exception.CustomError)
+	if got := customErr.Origin().Function; got != "github.com/tae2089/exception/exceptionkafka.TestWrapRecordsCaller" {
+		t.Fatalf("origin = %q, want the caller of Wrap", got)
+	}
+	if Wrap(nil, Message{}) != nil {
+		t.Fatal("Wrap(nil) should return nil")
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/tae2089/exception/exceptionlogr
+
+go 1.24.5
+
+require (
+	github.com/go-logr/logr v1.4.4
+	github.com/tae2089/exception v0.1.0
+)
+
+replace github.com/tae2089/exception => ../
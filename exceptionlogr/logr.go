@@ -0,0 +1,44 @@
+// Package exceptionlogr exposes exception.CustomError as logr key/value pairs, for
+// klog and controller-runtime loggers.
+package exceptionlogr
+
+import (
+	"errors"
+
+	"github.com/go-logr/logr"
+	"github.com/tae2089/exception"
+)
+
+// Keys used for the key/value pairs.
+const (
+	KeyCode   = "errorCode"
+	KeyID     = "errorID"
+	KeyTrace  = "errorTrace"
+	KeyFields = "errorFields"
+)
+
+// KeyValues returns the code, ID, trace and fields of the first CustomError in
+// err's chain as logr key/value pairs. It returns nil when err contains no CustomError.
+func KeyValues(err error) []any {
+	var customErr *exception.CustomError
+	if !errors.As(err, &customErr) || customErr == nil {
+		return nil
+	}
+	kv := []any{KeyCode, int(customErr.Code())}
+	if id := customErr.ID(); id != "" {
+		kv = append(kv, KeyID, id)
+	}
+	if customErr.Trace != "" {
+		kv = append(kv, KeyTrace, append([]string{customErr.Trace}, customErr.PreviousTraces...))
+	}
+	if fields := customErr.Fields(); fields != nil {
+		kv = append(kv, KeyFields, fields)
+	}
+	return kv
+}
+
+// Error logs err with logger.Error, adding the key/value pairs from KeyValues
+// before keysAndValues.
+func Error(logger logr.Logger, err error, msg string, keysAndValues ...any) {
+	logger.Error(err, msg, append(KeyValues(err), keysAndValues...)...)
+}
@@ -0,0 +1,55 @@
+package exceptionlogr
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/tae2089/exception"
+)
+
+func TestKeyValues(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		keys []string
+	}{
+		{"nil", nil, nil},
+		{"plain error", io.EOF, nil},
+		{"new", exception.New("user missing", exception.ErrorNotFound), []string{KeyCode, KeyID}},
+		{"wrapped with fields", exception.Wrap(io.EOF, exception.WithCode(exception.ErrorNotFound), exception.WithField("user", "42")), []string{KeyCode, KeyID, KeyTrace, KeyFields}},
+		{"in the chain", fmt.Errorf("load: %w", exception.NotFound("user missing")), []string{KeyCode, KeyID, KeyTrace}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kv := KeyValues(tt.err)
+			if len(kv) != 2*len(tt.keys) {
+				t.Fatalf("KeyValues = %v, want keys %v", kv, tt.keys)
+			}
+			for i, key := range tt.keys {
+				if kv[2*i] != key {
+					t.Fatalf("key %d = %v, want %s", i, kv[2*i], key)
+				}
+			}
+			if len(kv) > 0 && kv[1] != int(exception.ErrorNotFound) {
+				t.Fatalf("code = %v", kv[1])
+			}
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	var lines []string
+	logger := funcr.New(func(prefix, args string) { lines = append(lines, args) }, funcr.Options{})
+	Error(logger, exception.NotFound("user missing"), "load failed", "user", "42")
+	if len(lines) != 1 {
+		t.Fatalf("logged %d lines, want 1", len(lines))
+	}
+	for _, want := range []string{`"msg"="load failed"`, `"errorCode"=404`, `"errorTrace"=`, `"user"="42"`, `"error"="user missing"`} {
+		if !strings.Contains(lines[0], want) {
+			t.Fatalf("log line %s should contain %s", lines[0], want)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/tae2089/exception/exceptionlogrus
+
+go 1.24.5
+
+require (
+	github.com/sirupsen/logrus v1.10.2
+	github.com/tae2089/exception v0.1.0
+)
+
+require golang.org/x/sys v0.13.0 // indirect
+
+replace github.com/tae2089/exception => ../
@@ -0,0 +1,67 @@
+// Package exceptionlogrus integrates exception.CustomError with logrus.
+package exceptionlogrus
+
+import (
+	"errors"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tae2089/exception"
+)
+
+const (
+	FieldCode   = "error_code"
+	FieldTrace  = "error_trace"
+	FieldCause  = "error_cause"
+	FieldFields = "error_fields"
+)
+
+// Fields expands a CustomError into structured logrus fields.
+// It returns nil when err does not contain a CustomError.
+func Fields(err error) logrus.Fields {
+	var customErr *exception.CustomError
+	if !errors.As(err, &customErr) || customErr == nil {
+		return nil
+	}
+	fields := logrus.Fields{
+		FieldCode: int(customErr.Code()),
+	}
+	if customErr.Trace != "" {
+		fields[FieldTrace] = append([]string{customErr.Trace}, customErr.PreviousTraces...)
+	}
+	if cause := exception.Cause(customErr); cause != nil && cause != error(customErr) {
+		fields[FieldCause] = cause.Error()
+	}
+	if metadata := customErr.Fields(); metadata != nil {
+		fields[FieldFields] = metadata
+	}
+	return fields
+}
+
+// Hook is a logrus.Hook that expands CustomErrors logged under logrus.ErrorKey
+// into structured fields.
+type Hook struct {
+	levels []logrus.Level
+}
+
+// NewHook creates a Hook firing on the given levels, or on all levels when none are given.
+func NewHook(levels ...logrus.Level) *Hook {
+	if len(levels) == 0 {
+		levels = logrus.AllLevels
+	}
+	return &Hook{levels: levels}
+}
+
+func (h *Hook) Levels() []logrus.Level {
+	return h.levels
+}
+
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	err, ok := entry.Data[logrus.ErrorKey].(error)
+	if !ok {
+		return nil
+	}
+	for k, v := range Fields(err) {
+		entry.Data[k] = v
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package exceptionlogrus
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tae2089/exception"
+)
+
+func TestFields(t *testing.T) {
+	var typedNil *exception.CustomError
+	tests := []struct {
+		name      string
+		err       error
+		code      int
+		cause     any
+		traces    int
+		metadata  map[string]any
+		wantNoErr bool
+	}{
+		{
+			name:   "wrapped plain error",
+			err:    exception.WrapMessageWithCode(io.EOF, exception.ErrorDataNotFound, "read user"),
+			code:   int(exception.ErrorDataNotFound),
+			cause:  io.EOF.Error(),
+			traces: 1,
+		},
+		{
+			name:   "behind fmt wrapping",
+			err:    fmt.Errorf("handler: %w", exception.WrapMessageWithCode(io.EOF, exception.ErrorDataNotFound, "read user")),
+			code:   int(exception.ErrorDataNotFound),
+			cause:  io.EOF.Error(),
+			traces: 1,
+		},
+		{
+			name:   "wrapped twice",
+			err:    exception.WrapMessage(exception.WrapMessage(io.EOF, "inner"), "outer"),
+			code:   int(exception.ErrorInternalServer),
+			cause:  io.EOF.Error(),
+			traces: 2,
+		},
+		{
+			name:     "with metadata",
+			err:      exception.New("boom", exception.ErrorInternalServer),
+			code:     int(exception.ErrorInternalServer),
+			metadata: map[string]any{"user": 7},
+		},
+		{name: "plain error", err: io.EOF, wantNoErr: true},
+		{name: "nil", err: nil, wantNoErr: true},
+		{name: "typed nil", err: typedNil, wantNoErr: true},
+		{name: "typed nil behind fmt wrapping", err: fmt.Errorf("wrap: %w", typedNil), wantNoErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var customErr *exception.CustomError
+			if tt.metadata != nil && errors.As(tt.err, &customErr) {
+				exception.WithFields(tt.metadata)(customErr)
+			}
+			fields := Fields(tt.err)
+			if tt.wantNoErr {
+				if fields != nil {
+					t.Fatalf("Fields = %v, want nil", fields)
+				}
+				return
+			}
+			if fields[FieldCode] != tt.code {
+				t.Fatalf("%s = %v, want %d", FieldCode, fields[FieldCode], tt.code)
+			}
+			if fields[FieldCause] != tt.cause {
+				t.Fatalf("%s = %v, want %v", FieldCause, fields[FieldCause], tt.cause)
+			}
+			if traces, _ := fields[FieldTrace].([]string); len(traces) != tt.traces {
+				t.Fatalf("%s = %v, want %d entries", FieldTrace, fields[FieldTrace], tt.traces)
+			}
+			if metadata, _ := fields[FieldFields].(map[string]any); fmt.Sprint(metadata) != fmt.Sprint(tt.metadata) {
+				t.Fatalf("%s = %v, want %v", FieldFields, fields[FieldFields], tt.metadata)
+			}
+		})
+	}
+}
+
+func TestHookFire(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	hook := NewHook()
+	logger.AddHook(hook)
+
+	var fired *logrus.Entry
+	logger.AddHook(captureHook(func(e *logrus.Entry) { fired = e }))
+	logger.WithError(exception.WrapMessageWithCode(io.EOF, exception.ErrorDataNotFound, "read user")).Error("request failed")
+
+	if fired == nil {
+		t.Fatal("entry was not logged")
+	}
+	if fired.Data[FieldCode] != int(exception.ErrorDataNotFound) {
+		t.Fatalf("%s = %v", FieldCode, fired.Data[FieldCode])
+	}
+	if len(hook.Levels()) != len(logrus.AllLevels) {
+		t.Fatalf("Levels = %v, want all levels", hook.Levels())
+	}
+	if got := NewHook(logrus.ErrorLevel).Levels(); len(got) != 1 || got[0] != logrus.ErrorLevel {
+		t.Fatalf("Levels = %v", got)
+	}
+}
+
+type captureHook func(*logrus.Entry)
+
+func (h captureHook) Levels() []logrus.Level     { return logrus.AllLevels }
+func (h captureHook) Fire(e *logrus.Entry) error { h(e); return nil }
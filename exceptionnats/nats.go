@@ -0,0 +1,104 @@
+// Package exceptionnats converts between exception.CustomError and NATS service
+// API (micro) errors, carrying the error ID, code and domain across the
+// request-reply hop in headers.
+package exceptionnats
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/micro"
+	"github.com/tae2089/exception"
+)
+
+var exposeMessages bool
+
+// SetExposeMessages sets whether service errors without a public message carry
+// their error message, which may include internal details. By default the
+// default message of the code is sent instead. Enable it only between trusted
+// services. It should be called during initialization.
+func SetExposeMessages(enabled bool) {
+	exposeMessages = enabled
+}
+
+// ServiceError returns the code, description and headers micro.Request.Error
+// responds with for err. The code is the ErrorCode of the first CustomError in the
+// chain, the description its public message or, when it has none, the default
+// message of the code unless SetExposeMessages is enabled, and the headers those
+// written by exception.SetHTTPHeaders. Errors without a CustomError become
+// internal server errors with a generic description.
+func ServiceError(err error) (code, description string, headers micro.Headers) {
+	var customErr *exception.CustomError
+	if !errors.As(err, &customErr) || customErr == nil {
+		return strconv.Itoa(int(exception.ErrorInternalServer)), exception.DefaultMessage(exception.ErrorInternalServer), nil
+	}
+	description = customErr.PublicMessage()
+	if description == "" && exposeMessages {
+		description = customErr.Error()
+	}
+	if description == "" {
+		description = exception.DefaultMessage(customErr.Code())
+	}
+	h := make(http.Header)
+	exception.SetHTTPHeaders(h, customErr)
+	return strconv.Itoa(int(customErr.Code())), description, micro.Headers(h)
+}
+
+// Respond replies to req with err as a service error; see ServiceError.
+func Respond(req micro.Request, err error) error {
+	code, description, headers := ServiceError(err)
+	return req.Error(code, description, nil, micro.WithHeaders(headers))
+}
+
+// FromMessage rebuilds the remote CustomError from a reply carrying a service
+// error. It returns nil when msg is not an error reply.
+func FromMessage(msg *nats.Msg) *exception.CustomError {
+	if msg == nil {
+		return nil
+	}
+	h := http.Header(msg.Header)
+	code := h.Get(micro.ErrorCodeHeader)
+	if code == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(code)
+	if err != nil {
+		n = int(exception.ErrorInternalServer)
+	}
+	opts := []exception.CustomErrorOption{exception.WithRemote()}
+	if id := h.Get(exception.HeaderErrorID); id != "" {
+		opts = append(opts, exception.WithID(id))
+	}
+	if domain := h.Get(exception.HeaderErrorDomain); domain != "" {
+		opts = append(opts, exception.WithDomain(domain))
+	}
+	return exception.New(h.Get(micro.ErrorHeader), exception.ErrorCode(n), opts...)
+}
+
+// HandlerFunc is a micro handler returning an error.
+type HandlerFunc func(req micro.Request) error
+
+// Handler adapts fn to a micro.Handler that responds to errors with Respond. A
+// panic is logged with slog and passed to exception.Report, and the request gets a
+// generic internal server error carrying only the error ID, so the panic value
+// does not leak to the caller.
+func Handler(fn HandlerFunc) micro.Handler {
+	return micro.HandlerFunc(func(req micro.Request) {
+		defer func() {
+			if p := recover(); p != nil {
+				err := exception.Internalf("panic in %s: %v", req.Subject(), p)
+				ctx := context.Background()
+				slog.ErrorContext(ctx, err.Error(), slog.String("id", err.ID()), slog.String("trace", err.PrintTrace()))
+				exception.Report(ctx, err)
+				_ = Respond(req, err.WithPublic(exception.DefaultMessage(exception.ErrorInternalServer)))
+			}
+		}()
+		if err := fn(req); err != nil {
+			_ = Respond(req, err)
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package exceptionnats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/micro"
+	"github.com/tae2089/exception"
+)
+
+// fakeRequest records the error reply as the NATS message micro would publish.
+type fakeRequest struct {
+	reply *nats.Msg
+}
+
+func (r *fakeRequest) Respond([]byte, ...micro.RespondOpt) error { return nil }
+
+func (r *fakeRequest) RespondJSON(any, ...micro.RespondOpt) error { return nil }
+
+func (r *fakeRequest) Error(code, description string, data []byte, opts ...micro.RespondOpt) error {
+	msg := &nats.Msg{Data: data, Header: nats.Header{
+		micro.ErrorHeader:     []string{description},
+		micro.ErrorCodeHeader: []string{code},
+	}}
+	for _, opt := range opts {
+		opt(msg)
+	}
+	r.reply = msg
+	return nil
+}
+
+func (r *fakeRequest) Data() []byte { return nil }
+
+func (r *fakeRequest) Headers() micro.Headers { return nil }
+
+func (r *fakeRequest) Subject() string { return "users.get" }
+
+func (r *fakeRequest) Reply() string { return "_INBOX.1" }
+
+func TestServiceError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		code        string
+		description string
+		id          string
+	}{
+		{"plain error", io.EOF, "500", "internal server error", ""},
+		{"custom error", exception.New("user 42 missing", exception.ErrorNotFound, exception.WithID("e-1")), "404", "not found", "e-1"},
+		{"public message", exception.New("row 7 missing", exception.ErrorNotFound, exception.WithID("e-2"), exception.WithPublicMessage("not found")),
+			"404", "not found", "e-2"},
+		{"fmt wrapped", fmt.Errorf("get: %w", exception.New("taken", exception.ErrorConflict, exception.WithID("e-3"))), "409", "conflict", "e-3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, description, headers := ServiceError(tt.err)
+			if code != tt.code || description != tt.description {
+				t.Fatalf("ServiceError = %s %q", code, description)
+			}
+			if got := http.Header(headers).Get(exception.HeaderErrorID); got != tt.id {
+				t.Fatalf("ID header = %q, want %q", got, tt.id)
+			}
+		})
+	}
+}
+
+func TestSetExposeMessages(t *testing.T) {
+	SetExposeMessages(true)
+	t.Cleanup(func() { SetExposeMessages(false) })
+	err := exception.Wrap(exception.New("user 42 missing", exception.ErrorNotFound), exception.WithMessage("load user"))
+	if _, description, _ := ServiceError(err); description != "load user" {
+		t.Fatalf("description = %q", description)
+	}
+}
+
+func TestFromMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		msg    *nats.Msg
+		code   exception.ErrorCode
+		text   string
+		id     string
+		domain string
+	}{
+		{"nil", nil, 0, "", "", ""},
+		{"success reply", &nats.Msg{Data: []byte("ok")}, 0, "", "", ""},
+		{"error reply", &nats.Msg{Header: nats.Header{
+			micro.ErrorHeader: {"user missing"}, micro.ErrorCodeHeader: {"404"},
+			exception.HeaderErrorID: {"e-1"}, exception.HeaderErrorDomain: {"user"},
+		}}, exception.ErrorNotFound, "user missing", "e-1", "user"},
+		{"non-numeric code", &nats.Msg{Header: nats.Header{micro.ErrorHeader: {"boom"}, micro.ErrorCodeHeader: {"E_BOOM"}}},
+			exception.ErrorInternalServer, "boom", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromMessage(tt.msg)
+			if tt.code == 0 {
+				if got != nil {
+					t.Fatalf("FromMessage = %v, want nil", got)
+				}
+				return
+			}
+			if got.Code() != tt.code || got.Error() != tt.text || got.Domain() != tt.domain || !got.IsRemote() {
+				t.Fatalf("FromMessage = %q (%d, %q, remote %v)", got.Error(), got.Code(), got.Domain(), got.IsRemote())
+			}
+			if tt.id != "" && got.ID() != tt.id {
+				t.Fatalf("ID = %q, want %q", got.ID(), tt.id)
+			}
+		})
+	}
+}
+
+// recordingReporter records the errors reported to it.
+type recordingReporter struct {
+	errs []error
+}
+
+func (r *recordingReporter) Report(_ context.Context, err error) {
+	r.errs = append(r.errs, err)
+}
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		fn       HandlerFunc
+		code     exception.ErrorCode
+		text     string
+		reported bool
+	}{
+		{"success", func(micro.Request) error { return nil }, 0, "", false},
+		{"error", func(micro.Request) error { return exception.New("user missing", exception.ErrorNotFound) },
+			exception.ErrorNotFound, "not found", false},
+		{"panic", func(micro.Request) error { panic("db password is hunter2") },
+			exception.ErrorInternalServer, "internal server error", true},
+	}
+	logger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	t.Cleanup(func() { slog.SetDefault(logger) })
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter := &recordingReporter{}
+			exception.SetReporter(reporter)
+			t.Cleanup(func() { exception.SetReporter(nil) })
+			req := &fakeRequest{}
+			Handler(tt.fn).Handle(req)
+			got := FromMessage(req.reply)
+			if tt.code == 0 {
+				if req.reply != nil {
+					t.Fatalf("replied with %v", req.reply.Header)
+				}
+				return
+			}
+			if got == nil || got.Code() != tt.code || got.Error() != tt.text {
+				t.Fatalf("reply = %v", req.reply.Header)
+			}
+			if (len(reporter.errs) == 1) != tt.reported {
+				t.Fatalf("reported %v", reporter.errs)
+			}
+			if !tt.reported {
+				return
+			}
+			// 패닉 내용은 보고에만 남고 응답에는 ID만 실림
+			var reported *exception.CustomError
+			if !errors.As(reporter.errs[0], &reported) || !strings.Contains(reported.Error(), "hunter2") || reported.Trace == "" {
+				t.Fatalf("reported %v", reporter.errs[0])
+			}
+			if got.ID() != reported.ID() {
+				t.Fatalf("reply ID %q, want the reported error's %q", got.ID(), reported.ID())
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// Package exceptionopenapi generates OpenAPI 3 components describing the error
+// responses written by exception.WriteHTTP, from the registered code catalog.
+//
+// The structs marshal to OpenAPI JSON and can be embedded into specs built by
+// other tools. They describe the default response body; a custom encoder set with
+// exception.SetResponseEncoder needs its own schema.
+package exceptionopenapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/tae2089/exception"
+)
+
+// Schema is an OpenAPI schema object.
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Enum        []any              `json:"enum,omitempty"`
+	AllOf       []*Schema          `json:"allOf,omitempty"`
+	Example     any                `json:"example,omitempty"`
+}
+
+// Example is an OpenAPI example object.
+type Example struct {
+	Summary string `json:"summary,omitempty"`
+	Value   any    `json:"value"`
+}
+
+// MediaType is an OpenAPI media type object.
+type MediaType struct {
+	Schema   *Schema             `json:"schema"`
+	Examples map[string]*Example `json:"examples,omitempty"`
+}
+
+// Response is an OpenAPI response object.
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// Components is an OpenAPI components object.
+type Components struct {
+	Schemas   map[string]*Schema   `json:"schemas"`
+	Responses map[string]*Response `json:"responses,omitempty"`
+}
+
+// Schema names used in the generated components.
+const (
+	ErrorSchema           = "Error"
+	ValidationErrorSchema = "ValidationError"
+)
+
+// ErrorSchemaRef is the reference to the Error schema.
+var ErrorSchemaRef = &Schema{Ref: "#/components/schemas/" + ErrorSchema}
+
+// Generate returns the Error and ValidationError schemas and one response per
+// HTTP status used by the registered codes, named after the status code, with an
+// example per code.
+func Generate() Components {
+	return Components{
+		Schemas: map[string]*Schema{
+			ErrorSchema:           errorSchema(),
+			ValidationErrorSchema: validationErrorSchema(),
+		},
+		Responses: Responses(),
+	}
+}
+
+func errorSchema() *Schema {
+	return &Schema{
+		Type:        "object",
+		Description: "Error written by exception.WriteHTTP.",
+		Properties: map[string]*Schema{
+			"id":       {Type: "string", Description: "Identifier of the error occurrence."},
+			"code":     {Type: "integer", Description: "Error code."},
+			"domain":   {Type: "string", Description: "Domain the error belongs to."},
+			"message":  {Type: "string", Description: "Message safe to show to end users."},
+			"hint":     {Type: "string", Description: "What the caller should check."},
+			"help_url": {Type: "string", Format: "uri", Description: "Documentation about the error code."},
+		},
+		Required: []string{"code", "message"},
+	}
+}
+
+// validationErrorSchema restricts the Error schema to the registered codes
+// reported with 400 Bad Request or 422 Unprocessable Entity.
+func validationErrorSchema() *Schema {
+	var codes []any
+	for _, info := range exception.Codes() {
+		if status := exception.HTTPStatus(info.Code); status == http.StatusBadRequest || status == http.StatusUnprocessableEntity {
+			codes = append(codes, int(info.Code))
+		}
+	}
+	if len(codes) == 0 {
+		codes = []any{http.StatusBadRequest, http.StatusUnprocessableEntity}
+	}
+	return &Schema{
+		Description: "Error caused by an invalid request.",
+		AllOf: []*Schema{
+			ErrorSchemaRef,
+			{Type: "object", Properties: map[string]*Schema{"code": {Type: "integer", Enum: codes}}},
+		},
+	}
+}
+
+// Responses returns one response per HTTP status used by the registered codes,
+// keyed by the status code, with an example per code.
+func Responses() map[string]*Response {
+	responses := make(map[string]*Response)
+	for _, info := range exception.Codes() {
+		status := strconv.Itoa(exception.HTTPStatus(info.Code))
+		resp, ok := responses[status]
+		if !ok {
+			resp = &Response{
+				Description: http.StatusText(exception.HTTPStatus(info.Code)),
+				Content: map[string]*MediaType{
+					"application/json": {Schema: ErrorSchemaRef, Examples: map[string]*Example{}},
+				},
+			}
+			responses[status] = resp
+		}
+		name := info.Name
+		if name == "" {
+			name = info.Code.Name()
+		}
+		// 응답에는 공개 메시지가 실리고, 없을 때만 기본 메시지
+		msg := info.PublicMessage
+		if msg == "" {
+			msg = info.Message
+		}
+		value := map[string]any{"code": int(info.Code), "message": msg}
+		if info.Domain != "" {
+			value["domain"] = info.Domain
+		}
+		if info.HelpURL != "" {
+			value["help_url"] = info.HelpURL
+		}
+		resp.Content["application/json"].Examples[name] = &Example{Summary: info.Description, Value: value}
+	}
+	return responses
+}
@@ -0,0 +1,93 @@
+package exceptionopenapi
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/tae2089/exception"
+)
+
+func init() {
+	exception.RegisterCode(exception.CodeInfo{Code: 402, Name: "PaymentDeclined", Domain: "billing", Message: "payment declined", PublicMessage: "Your payment was declined.", Description: "The card was declined."})
+	exception.RegisterCode(exception.CodeInfo{Code: 422, Name: "InvalidOrder", Message: "invalid order", HelpURL: "https://example.com/errors/invalid-order"})
+	exception.RegisterCode(exception.CodeInfo{Code: 40099, Message: "out of range"})
+}
+
+func TestResponses(t *testing.T) {
+	responses := Responses()
+	tests := []struct {
+		status  string
+		example string
+		want    map[string]any
+		summary string
+	}{
+		{"402", "PaymentDeclined", map[string]any{"code": 402, "message": "Your payment was declined.", "domain": "billing"}, "The card was declined."},
+		{"422", "InvalidOrder", map[string]any{"code": 422, "message": "invalid order", "help_url": "https://example.com/errors/invalid-order"}, ""},
+		{"500", "Code40099", map[string]any{"code": 40099, "message": "out of range"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			resp, ok := responses[tt.status]
+			if !ok {
+				t.Fatalf("no %s response in %v", tt.status, responses)
+			}
+			media := resp.Content["application/json"]
+			if media.Schema != ErrorSchemaRef || resp.Description == "" {
+				t.Fatalf("response = %+v", resp)
+			}
+			example, ok := media.Examples[tt.example]
+			if !ok {
+				t.Fatalf("no %s example", tt.example)
+			}
+			got, _ := json.Marshal(example.Value)
+			want, _ := json.Marshal(tt.want)
+			if string(got) != string(want) || example.Summary != tt.summary {
+				t.Fatalf("example = %s %q, want %s %q", got, example.Summary, want, tt.summary)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	components := Generate()
+	tests := []struct {
+		name     string
+		required []string
+	}{
+		{ErrorSchema, []string{"code", "message"}},
+		{ValidationErrorSchema, nil},
+	}
+	for _, tt := range tests {
+		schema, ok := components.Schemas[tt.name]
+		if !ok || !slices.Equal(schema.Required, tt.required) {
+			t.Fatalf("schema %s = %+v", tt.name, schema)
+		}
+	}
+	enum := components.Schemas[ValidationErrorSchema].AllOf[1].Properties["code"].Enum
+	if !slices.Equal(enum, []any{422}) {
+		t.Fatalf("validation codes = %v, want [422]", enum)
+	}
+	if _, err := json.Marshal(components); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestErrorSchemaMatchesWriteHTTP checks that the Error schema describes every
+// member of the body written by exception.WriteHTTP.
+func TestErrorSchemaMatchesWriteHTTP(t *testing.T) {
+	rec := httptest.NewRecorder()
+	exception.WriteHTTP(rec, exception.New("order 7 invalid", 422, exception.WithDomain("orders"),
+		exception.WithPublicMessage("invalid order"), exception.WithHint("check the items")))
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	got := slices.Sorted(maps.Keys(errorSchema().Properties))
+	want := slices.Sorted(maps.Keys(body))
+	if !slices.Equal(got, want) {
+		t.Fatalf("schema properties = %v, body members = %v", got, want)
+	}
+}
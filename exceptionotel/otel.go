@@ -0,0 +1,110 @@
+// Package exceptionotel records exception.CustomErrors as OpenTelemetry metrics:
+// a counter of reported errors, fed through the reporter's sinks, and a duration
+// histogram for handlers returning errors.
+package exceptionotel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/tae2089/exception"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Instrument names.
+const (
+	ErrorsName   = "exception.errors"
+	DurationName = "exception.handler.duration"
+)
+
+// Attribute keys.
+const (
+	KeyCode     = "code"
+	KeyDomain   = "domain"
+	KeySeverity = "severity"
+)
+
+// Metrics holds the instruments errors are recorded with.
+type Metrics struct {
+	errors   metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+// New creates the instruments with meter.
+func New(meter metric.Meter) (*Metrics, error) {
+	errs, err := meter.Int64Counter(ErrorsName,
+		metric.WithDescription("Number of errors reported."),
+		metric.WithUnit("{error}"))
+	if err != nil {
+		return nil, err
+	}
+	duration, err := meter.Float64Histogram(DurationName,
+		metric.WithDescription("Duration of handlers returning errors."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	return &Metrics{errors: errs, duration: duration}, nil
+}
+
+// Record adds err to the error counter with its code, domain and severity as
+// attributes. Errors that are not CustomErrors are counted as internal server
+// errors; nil errors are ignored.
+func (m *Metrics) Record(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	m.errors.Add(ctx, 1, metric.WithAttributes(attributes(err)...))
+}
+
+// Send implements exception.Sink, so the counter can be fed by a reporter.
+func (m *Metrics) Send(ctx context.Context, errs []*exception.CustomError) error {
+	for _, e := range errs {
+		m.Record(ctx, e)
+	}
+	return nil
+}
+
+// Handler wraps h, recording the duration of every request in the histogram and
+// counting the errors it returns. Requests without an error are recorded without
+// attributes; errors caused by the client closing the request are recorded as
+// exception.ErrorClientClosedRequest, as exception.HandlerFunc writes them.
+func (m *Metrics) Handler(h exception.HandlerFunc) http.Handler {
+	return exception.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		start := time.Now()
+		err := h(w, r)
+		elapsed := time.Since(start).Seconds()
+		ctx := r.Context()
+		if err == nil {
+			m.duration.Record(ctx, elapsed)
+			return nil
+		}
+		attrs := attributes(err)
+		if exception.ClientClosed(r, err) {
+			attrs[0] = attribute.Int(KeyCode, int(exception.ErrorClientClosedRequest))
+			attrs[2] = attribute.String(KeySeverity, exception.SeverityInfo.String())
+		}
+		set := metric.WithAttributes(attrs...)
+		m.duration.Record(ctx, elapsed, set)
+		m.errors.Add(ctx, 1, set)
+		return err
+	})
+}
+
+// attributes returns the code, domain and severity of err, in that order.
+func attributes(err error) []attribute.KeyValue {
+	code := exception.ErrorInternalServer
+	var domain string
+	var customErr *exception.CustomError
+	if errors.As(err, &customErr) && customErr != nil {
+		code, domain = customErr.Code(), customErr.Domain()
+	}
+	return []attribute.KeyValue{
+		attribute.Int(KeyCode, int(code)),
+		attribute.String(KeyDomain, domain),
+		attribute.String(KeySeverity, exception.SeverityOf(err).String()),
+	}
+}
@@ -0,0 +1,125 @@
+package exceptionotel
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tae2089/exception"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func newMetrics(t *testing.T) (*Metrics, *sdkmetric.ManualReader) {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	m, err := New(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, reader
+}
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	data := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			data[m.Name] = m.Data
+		}
+	}
+	return data
+}
+
+func attrs(code exception.ErrorCode, domain, severity string) attribute.Set {
+	return attribute.NewSet(
+		attribute.Int(KeyCode, int(code)),
+		attribute.String(KeyDomain, domain),
+		attribute.String(KeySeverity, severity),
+	)
+}
+
+func TestRecord(t *testing.T) {
+	m, reader := newMetrics(t)
+	ctx := context.Background()
+	m.Record(ctx, nil)
+	m.Record(ctx, exception.New("user missing", exception.ErrorNotFound, exception.WithDomain("users")))
+	m.Record(ctx, exception.Wrap(io.EOF, exception.WithCode(exception.ErrorNotFound), exception.WithDomain("users")))
+	if err := m.Send(ctx, []*exception.CustomError{exception.New("boom", exception.ErrorInternalServer, exception.WithSeverity(exception.SeverityCritical))}); err != nil {
+		t.Fatal(err)
+	}
+	m.Record(ctx, io.EOF)
+
+	sum, ok := collect(t, reader)[ErrorsName].(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("%s is not an int64 sum", ErrorsName)
+	}
+	want := []struct {
+		attrs attribute.Set
+		value int64
+	}{
+		{attrs(exception.ErrorNotFound, "users", "warning"), 2},
+		{attrs(exception.ErrorInternalServer, "", "critical"), 1},
+		{attrs(exception.ErrorInternalServer, "", "error"), 1},
+	}
+	if len(sum.DataPoints) != len(want) {
+		t.Fatalf("data points = %+v", sum.DataPoints)
+	}
+	for _, w := range want {
+		var found bool
+		for _, dp := range sum.DataPoints {
+			if dp.Attributes.Equals(&w.attrs) {
+				found = dp.Value == w.value
+			}
+		}
+		if !found {
+			t.Fatalf("data points = %+v, want %v = %d", sum.DataPoints, w.attrs.ToSlice(), w.value)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		attrs attribute.Set
+	}{
+		{"ok", nil, attribute.NewSet()},
+		{"error", exception.NotFound("user missing"), attrs(exception.ErrorNotFound, "", "warning")},
+		{"client closed", http.ErrAbortHandler, attrs(exception.ErrorClientClosedRequest, "", "info")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, reader := newMetrics(t)
+			h := m.Handler(func(w http.ResponseWriter, r *http.Request) error { return tt.err })
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			data := collect(t, reader)
+			hist, ok := data[DurationName].(metricdata.Histogram[float64])
+			if !ok || len(hist.DataPoints) != 1 {
+				t.Fatalf("%s = %+v", DurationName, data[DurationName])
+			}
+			if dp := hist.DataPoints[0]; dp.Count != 1 || !dp.Attributes.Equals(&tt.attrs) {
+				t.Fatalf("duration = %+v", dp)
+			}
+			// 에러만 카운터에 기록
+			sum, _ := data[ErrorsName].(metricdata.Sum[int64])
+			if tt.err == nil {
+				if len(sum.DataPoints) != 0 {
+					t.Fatalf("errors = %+v", sum.DataPoints)
+				}
+				return
+			}
+			if len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 1 || !sum.DataPoints[0].Attributes.Equals(&tt.attrs) {
+				t.Fatalf("errors = %+v", sum.DataPoints)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package exceptionstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tae2089/exception"
+)
+
+// Dialect selects the SQL flavor of a SQLStore.
+type Dialect int
+
+const (
+	SQLite Dialect = iota
+	Postgres
+)
+
+// DefaultTable is the table a SQLStore uses when none is given.
+const DefaultTable = "exception_errors"
+
+// SQLStore is an ErrorStore writing to a SQL database through database/sql. The
+// caller opens the database with the driver of its choice, so the package adds no
+// driver dependency.
+type SQLStore struct {
+	db      *sql.DB
+	dialect Dialect
+	table   string
+}
+
+// NewSQLStore creates a store using table in db, or DefaultTable when table is
+// empty. The table name is inserted into statements as is and must be trusted.
+func NewSQLStore(db *sql.DB, dialect Dialect, table string) *SQLStore {
+	if table == "" {
+		table = DefaultTable
+	}
+	return &SQLStore{db: db, dialect: dialect, table: table}
+}
+
+// CreateTable creates the table and its indexes if they do not exist.
+func (s *SQLStore) CreateTable(ctx context.Context) error {
+	timeType := "TIMESTAMP"
+	if s.dialect == Postgres {
+		timeType = "TIMESTAMPTZ"
+	}
+	statements := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id TEXT NOT NULL,
+	fingerprint TEXT NOT NULL,
+	code INTEGER NOT NULL,
+	message TEXT NOT NULL,
+	reported_at %s NOT NULL,
+	data TEXT NOT NULL
+)`, s.table, timeType),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %[1]s_fingerprint_idx ON %[1]s (fingerprint, reported_at)", s.table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %[1]s_reported_at_idx ON %[1]s (reported_at)", s.table),
+	}
+	for _, statement := range statements {
+		if _, err := s.db.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// placeholder returns the n-th (1-based) bind parameter of the dialect.
+func (s *SQLStore) placeholder(n int) string {
+	if s.dialect == Postgres {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+func (s *SQLStore) Save(ctx context.Context, records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf(
+		"INSERT INTO %s (id, fingerprint, code, message, reported_at, data) VALUES (%s, %s, %s, %s, %s, %s)",
+		s.table, s.placeholder(1), s.placeholder(2), s.placeholder(3), s.placeholder(4), s.placeholder(5), s.placeholder(6)))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, r := range records {
+		if _, err := stmt.ExecContext(ctx, r.ID, r.Fingerprint, int(r.Code), r.Message, r.Time.UTC(), string(r.Data)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore) Query(ctx context.Context, q Query) ([]Record, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	where := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, condition+" "+s.placeholder(len(args)))
+	}
+	if q.Fingerprint != "" {
+		where("fingerprint =", q.Fingerprint)
+	}
+	if q.Code != 0 {
+		where("code =", int(q.Code))
+	}
+	if !q.Since.IsZero() {
+		where("reported_at >=", q.Since.UTC())
+	}
+	if !q.Until.IsZero() {
+		where("reported_at <", q.Until.UTC())
+	}
+	query := fmt.Sprintf("SELECT id, fingerprint, code, message, reported_at, data FROM %s", s.table)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY reported_at"
+	if q.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", q.Limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var records []Record
+	for rows.Next() {
+		var (
+			r    Record
+			code int
+			data string
+			t    time.Time
+		)
+		if err := rows.Scan(&r.ID, &r.Fingerprint, &code, &r.Message, &t, &data); err != nil {
+			return nil, err
+		}
+		r.Code, r.Time, r.Data = exception.ErrorCode(code), t, []byte(data)
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
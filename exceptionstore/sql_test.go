@@ -0,0 +1,227 @@
+package exceptionstore
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tae2089/exception"
+)
+
+// fakeDB is a database/sql driver recording the statements it runs and answering
+// queries with preset rows.
+type fakeDB struct {
+	mu        sync.Mutex
+	execs     []fakeCall
+	queries   []fakeCall
+	rows      [][]driver.Value
+	commits   int
+	rollbacks int
+}
+
+type fakeCall struct {
+	query string
+	args  []driver.Value
+}
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: db}, nil }
+
+func (db *fakeDB) Driver() driver.Driver { return nil }
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return &fakeTx{db: c.db}, nil }
+
+type fakeTx struct {
+	db *fakeDB
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.db.mu.Lock()
+	defer tx.db.mu.Unlock()
+	tx.db.commits++
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.db.mu.Lock()
+	defer tx.db.mu.Unlock()
+	tx.db.rollbacks++
+	return nil
+}
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error { return nil }
+
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.execs = append(s.db.execs, fakeCall{query: s.query, args: args})
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.queries = append(s.db.queries, fakeCall{query: s.query, args: args})
+	return &fakeRows{rows: s.db.rows}, nil
+}
+
+type fakeRows struct {
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"id", "fingerprint", "code", "message", "reported_at", "data"}
+}
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func newFakeStore(t *testing.T, dialect Dialect, table string) (*SQLStore, *fakeDB) {
+	fake := &fakeDB{}
+	db := sql.OpenDB(fake)
+	t.Cleanup(func() { db.Close() })
+	return NewSQLStore(db, dialect, table), fake
+}
+
+func TestSQLStoreCreateTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  Dialect
+		table    string
+		wantType string
+		created  string
+	}{
+		{"sqlite", SQLite, "", "reported_at TIMESTAMP NOT NULL", DefaultTable},
+		{"postgres", Postgres, "errors", "reported_at TIMESTAMPTZ NOT NULL", "errors"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fake := newFakeStore(t, tt.dialect, tt.table)
+			if err := store.CreateTable(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if len(fake.execs) != 3 {
+				t.Fatalf("ran %d statements, want 3", len(fake.execs))
+			}
+			create := fake.execs[0].query
+			if !strings.Contains(create, "CREATE TABLE IF NOT EXISTS "+tt.created+" (") || !strings.Contains(create, tt.wantType) {
+				t.Fatalf("create statement = %s", create)
+			}
+			if !strings.Contains(fake.execs[1].query, tt.created+"_fingerprint_idx ON "+tt.created) {
+				t.Fatalf("index statement = %s", fake.execs[1].query)
+			}
+		})
+	}
+}
+
+func TestSQLStoreSave(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		values  string
+	}{
+		{"sqlite", SQLite, "VALUES (?, ?, ?, ?, ?, ?)"},
+		{"postgres", Postgres, "VALUES ($1, $2, $3, $4, $5, $6)"},
+	}
+	at := time.Date(2026, 10, 14, 9, 0, 0, 0, time.FixedZone("KST", 9*60*60))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fake := newFakeStore(t, tt.dialect, "")
+			record, err := NewRecord(exception.New("boom", exception.ErrorConflict, exception.WithID("e-1")), at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Save(context.Background(), []Record{record, record}); err != nil {
+				t.Fatal(err)
+			}
+			if len(fake.execs) != 2 || fake.commits != 1 {
+				t.Fatalf("%d inserts and %d commits, want 2 and 1", len(fake.execs), fake.commits)
+			}
+			insert := fake.execs[0]
+			if want := "INSERT INTO " + DefaultTable + " (id, fingerprint, code, message, reported_at, data) " + tt.values; insert.query != want {
+				t.Fatalf("insert = %s, want %s", insert.query, want)
+			}
+			want := []driver.Value{"e-1", record.Fingerprint, int64(exception.ErrorConflict), "boom", at.UTC(), string(record.Data)}
+			if !slices.EqualFunc(insert.args, want, func(a, b driver.Value) bool { return a == b }) {
+				t.Fatalf("args = %v, want %v", insert.args, want)
+			}
+		})
+	}
+	store, fake := newFakeStore(t, SQLite, "")
+	if err := store.Save(context.Background(), nil); err != nil || fake.commits+fake.rollbacks != 0 {
+		t.Fatal("saving no records should not open a transaction")
+	}
+}
+
+func TestSQLStoreQuery(t *testing.T) {
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	until := since.Add(24 * time.Hour)
+	tests := []struct {
+		name    string
+		dialect Dialect
+		q       Query
+		where   string
+		args    []driver.Value
+	}{
+		{"all", SQLite, Query{}, " ORDER BY reported_at", nil},
+		{"sqlite filters", SQLite, Query{Fingerprint: "fp", Code: 409, Since: since, Until: until, Limit: 5},
+			" WHERE fingerprint = ? AND code = ? AND reported_at >= ? AND reported_at < ? ORDER BY reported_at LIMIT 5",
+			[]driver.Value{"fp", int64(409), since, until}},
+		{"postgres filters", Postgres, Query{Code: 409, Since: since},
+			" WHERE code = $1 AND reported_at >= $2 ORDER BY reported_at", []driver.Value{int64(409), since}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, fake := newFakeStore(t, tt.dialect, "")
+			fake.rows = [][]driver.Value{{"e-1", "fp", int64(409), "boom", since, `{"version":2,"code":409,"message":"boom"}`}}
+			records, err := store.Query(context.Background(), tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			call := fake.queries[0]
+			if want := "SELECT id, fingerprint, code, message, reported_at, data FROM " + DefaultTable + tt.where; call.query != want {
+				t.Fatalf("query = %s, want %s", call.query, want)
+			}
+			if !slices.EqualFunc(call.args, tt.args, func(a, b driver.Value) bool { return a == b }) {
+				t.Fatalf("args = %v, want %v", call.args, tt.args)
+			}
+			if len(records) != 1 || records[0].ID != "e-1" || records[0].Code != 409 || !records[0].Time.Equal(since) {
+				t.Fatalf("records = %+v", records)
+			}
+			decoded, err := records[0].Err()
+			if err != nil || decoded.Error() != "boom" {
+				t.Fatalf("Err = %v, %v", decoded, err)
+			}
+		})
+	}
+}
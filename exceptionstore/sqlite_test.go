@@ -0,0 +1,84 @@
+package exceptionstore
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tae2089/exception"
+	_ "modernc.org/sqlite"
+)
+
+// TestSQLStoreSQLite runs the statements of SQLStore against a real SQLite
+// database, which the fake driver of the other tests accepts without checking.
+func TestSQLStoreSQLite(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "errors.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	ctx := context.Background()
+	store := NewSQLStore(db, SQLite, "")
+	// 두 번 생성해도 실패하지 않아야 함
+	for range 2 {
+		if err := store.CreateTable(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	base := time.Date(2026, 10, 14, 9, 0, 0, 0, time.FixedZone("KST", 9*60*60))
+	var saved []Record
+	for i, code := range []exception.ErrorCode{exception.ErrorNotFound, exception.ErrorConflict, exception.ErrorNotFound} {
+		record, err := NewRecord(exception.New("boom", code), base.Add(time.Duration(2-i)*time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		saved = append(saved, record)
+	}
+	if err := store.Save(ctx, saved); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		q    Query
+		want []string
+	}{
+		{"all oldest first", Query{}, []string{saved[2].ID, saved[1].ID, saved[0].ID}},
+		{"by code", Query{Code: exception.ErrorNotFound}, []string{saved[2].ID, saved[0].ID}},
+		{"by fingerprint", Query{Fingerprint: saved[1].Fingerprint}, []string{saved[1].ID}},
+		{"limit", Query{Limit: 1}, []string{saved[2].ID}},
+		{"since", Query{Since: base.Add(time.Minute)}, []string{saved[1].ID, saved[0].ID}},
+		{"until", Query{Until: base.Add(time.Minute)}, []string{saved[2].ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := store.Query(ctx, tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			for _, r := range records {
+				ids = append(ids, r.ID)
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Fatalf("ids = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+
+	records, err := store.Query(ctx, Query{Fingerprint: saved[0].Fingerprint, Code: exception.ErrorNotFound, Limit: 1})
+	if err != nil || len(records) != 1 {
+		t.Fatalf("records = %+v, %v", records, err)
+	}
+	got := records[0]
+	if got.ID != saved[2].ID || got.Code != exception.ErrorNotFound || got.Message != "boom" || !got.Time.Equal(saved[2].Time) {
+		t.Fatalf("record = %+v, want %+v", got, saved[2])
+	}
+	decoded, err := got.Err()
+	if err != nil || decoded.Error() != "boom" || decoded.ID() != saved[2].ID {
+		t.Fatalf("Err = %v, %v", decoded, err)
+	}
+}
@@ -0,0 +1,93 @@
+// Package exceptionstore keeps a history of reported errors without an external
+// service. An ErrorStore is plugged into an exception reporter through Sink;
+// FileStore appends to a JSONL file and SQLStore writes to SQLite or Postgres
+// through database/sql.
+package exceptionstore
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/tae2089/exception"
+)
+
+// Record is a stored error.
+type Record struct {
+	ID          string              `json:"id"`
+	Fingerprint string              `json:"fingerprint"`
+	Code        exception.ErrorCode `json:"code"`
+	Message     string              `json:"message"`
+	Time        time.Time           `json:"time"`
+	// Data is the error as written by exception.Encode.
+	Data json.RawMessage `json:"data"`
+}
+
+// NewRecord creates the record of err reported at t.
+func NewRecord(err *exception.CustomError, t time.Time) (Record, error) {
+	data, encodeErr := exception.Encode(err)
+	if encodeErr != nil {
+		return Record{}, encodeErr
+	}
+	return Record{
+		ID:          err.ID(),
+		Fingerprint: err.Fingerprint(),
+		Code:        err.Code(),
+		Message:     err.Error(),
+		Time:        t,
+		Data:        data,
+	}, nil
+}
+
+// Err decodes the stored error; see exception.Decode.
+func (r Record) Err() (*exception.CustomError, error) {
+	return exception.Decode(r.Data)
+}
+
+// Query selects stored records. Zero-valued fields match every record.
+type Query struct {
+	Fingerprint string
+	Code        exception.ErrorCode
+	// Since and Until bound the report time, inclusive and exclusive.
+	Since time.Time
+	Until time.Time
+	// Limit caps the number of records returned, oldest first.
+	Limit int
+}
+
+func (q Query) match(r Record) bool {
+	return (q.Fingerprint == "" || r.Fingerprint == q.Fingerprint) &&
+		(q.Code == 0 || r.Code == q.Code) &&
+		(q.Since.IsZero() || !r.Time.Before(q.Since)) &&
+		(q.Until.IsZero() || r.Time.Before(q.Until))
+}
+
+// ErrorStore saves reported errors and queries them back. Implementations must be
+// safe for concurrent use.
+type ErrorStore interface {
+	Save(ctx context.Context, records []Record) error
+	// Query returns the matching records ordered by report time.
+	Query(ctx context.Context, q Query) ([]Record, error)
+}
+
+// Sink returns an exception.Sink saving every reported error to store, stamped
+// with the time the error was created, or the time it is sent for errors without
+// a creation time, so buffering reporters do not skew the history.
+func Sink(store ErrorStore) exception.Sink {
+	return exception.SinkFunc(func(ctx context.Context, errs []*exception.CustomError) error {
+		now := time.Now()
+		records := make([]Record, 0, len(errs))
+		for _, e := range errs {
+			t := e.CreatedAt()
+			if t.IsZero() {
+				t = now
+			}
+			record, err := NewRecord(e, t)
+			if err != nil {
+				return err
+			}
+			records = append(records, record)
+		}
+		return store.Save(ctx, records)
+	})
+}
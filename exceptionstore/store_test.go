@@ -0,0 +1,138 @@
+package exceptionstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tae2089/exception"
+)
+
+func TestQueryMatch(t *testing.T) {
+	at := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	record := Record{Fingerprint: "fp", Code: exception.ErrorConflict, Time: at}
+	tests := []struct {
+		name string
+		q    Query
+		want bool
+	}{
+		{"empty", Query{}, true},
+		{"fingerprint", Query{Fingerprint: "fp"}, true},
+		{"other fingerprint", Query{Fingerprint: "other"}, false},
+		{"code", Query{Code: exception.ErrorConflict}, true},
+		{"other code", Query{Code: exception.ErrorNotFound}, false},
+		{"since is inclusive", Query{Since: at}, true},
+		{"after since", Query{Since: at.Add(time.Second)}, false},
+		{"until is exclusive", Query{Until: at}, false},
+		{"before until", Query{Until: at.Add(time.Second)}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.q.match(record); got != tt.want {
+			t.Errorf("%s: match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.jsonl")
+	store := NewFileStore(path)
+	if records, err := store.Query(context.Background(), Query{}); err != nil || records != nil {
+		t.Fatalf("querying a missing file = %v, %v", records, err)
+	}
+	base := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	var saved []Record
+	for i, code := range []exception.ErrorCode{exception.ErrorNotFound, exception.ErrorConflict, exception.ErrorNotFound} {
+		record, err := NewRecord(exception.New("boom", code), base.Add(time.Duration(2-i)*time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		saved = append(saved, record)
+	}
+	if err := store.Save(context.Background(), saved[:2]); err != nil {
+		t.Fatal(err)
+	}
+	// 중간에 끊긴 줄 뒤에 이어 쓴 기록도 읽혀야 함
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"id": "torn`)
+	f.WriteString("\n\n")
+	f.Close()
+	if err := store.Save(context.Background(), saved[2:]); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		q    Query
+		want []string
+	}{
+		{"all oldest first", Query{}, []string{saved[2].ID, saved[1].ID, saved[0].ID}},
+		{"by code", Query{Code: exception.ErrorNotFound}, []string{saved[2].ID, saved[0].ID}},
+		{"limit", Query{Limit: 1}, []string{saved[2].ID}},
+		{"since", Query{Since: base.Add(time.Minute)}, []string{saved[1].ID, saved[0].ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := store.Query(context.Background(), tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := make([]string, len(records))
+			for i, r := range records {
+				ids[i] = r.ID
+			}
+			if len(ids) != len(tt.want) {
+				t.Fatalf("ids = %v, want %v", ids, tt.want)
+			}
+			for i := range ids {
+				if ids[i] != tt.want[i] {
+					t.Fatalf("ids = %v, want %v", ids, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSink(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "errors.jsonl"))
+	older := exception.New("first", exception.ErrorNotFound)
+	time.Sleep(time.Millisecond)
+	newer := exception.New("second", exception.ErrorConflict)
+	remote, err := exception.Decode([]byte(`{"code": 500, "message": "remote"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 보고 순서와 관계없이 에러가 생긴 시각으로 기록되고, 시각이 없으면 보낸 시각을 사용
+	errs := []*exception.CustomError{older, newer, remote}
+	before := time.Now()
+	if err := Sink(store).Send(context.Background(), []*exception.CustomError{newer, older, remote}); err != nil {
+		t.Fatal(err)
+	}
+	records, err := store.Query(context.Background(), Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("stored %d records, want 3", len(records))
+	}
+	for i, record := range records {
+		want := errs[i].CreatedAt()
+		if i == 2 {
+			want = record.Time
+			if want.Before(before) {
+				t.Fatalf("an error without a creation time should be stamped when sent: %+v", record)
+			}
+		}
+		if record.ID != errs[i].ID() || record.Fingerprint != errs[i].Fingerprint() || !record.Time.Equal(want) {
+			t.Fatalf("record %d = %+v", i, record)
+		}
+		got, err := record.Err()
+		if err != nil || got.Code() != errs[i].Code() || got.Error() != errs[i].Error() {
+			t.Fatalf("Err = %v, %v", got, err)
+		}
+	}
+}
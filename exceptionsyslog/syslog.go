@@ -0,0 +1,149 @@
+// Package exceptionsyslog writes exception.CustomErrors to syslog, mapping their
+// severity to syslog levels and their metadata to RFC 5424 structured data. It
+// works with *log/syslog.Writer and any other writer with the same level methods.
+package exceptionsyslog
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tae2089/exception"
+)
+
+// Level is a syslog severity level as defined by RFC 5424.
+type Level int
+
+const (
+	LevelEmergency Level = iota
+	LevelAlert
+	LevelCritical
+	LevelError
+	LevelWarning
+	LevelNotice
+	LevelInfo
+	LevelDebug
+)
+
+// SDID is the structured data ID of the element holding the error's metadata.
+// 32473 is the private enterprise number reserved for documentation; deployments
+// registered with their own number can replace it during initialization.
+var SDID = "exception@32473"
+
+// LevelOf maps the severity of err to a syslog level; see exception.SeverityOf.
+func LevelOf(err error) Level {
+	switch exception.SeverityOf(err) {
+	case exception.SeverityCritical:
+		return LevelCritical
+	case exception.SeverityWarning:
+		return LevelWarning
+	case exception.SeverityInfo:
+		return LevelInfo
+	case exception.SeverityDebug:
+		return LevelDebug
+	}
+	return LevelError
+}
+
+// StructuredData formats the ID, code, domain, fingerprint and fields of the first
+// CustomError in err's chain as an RFC 5424 SD-ELEMENT. It returns "-", the nil
+// value, when err contains no CustomError.
+func StructuredData(err error) string {
+	var customErr *exception.CustomError
+	if !errors.As(err, &customErr) || customErr == nil {
+		return "-"
+	}
+	var sb strings.Builder
+	sb.WriteString("[" + SDID)
+	param := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&sb, " %s=\"%s\"", paramName(name), escape(value))
+		}
+	}
+	param("id", customErr.ID())
+	param("code", strconv.Itoa(int(customErr.Code())))
+	param("domain", customErr.Domain())
+	param("fingerprint", customErr.Fingerprint())
+	fields := customErr.Fields()
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		param(key, fmt.Sprint(fields[key]))
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
+// paramName makes name a valid SD-NAME: at most 32 printable ASCII characters
+// other than '=', ' ', ']' and '"'.
+func paramName(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		if c <= ' ' || c > '~' || c == '=' || c == ']' || c == '"' {
+			b[i] = '_'
+		}
+	}
+	if len(b) > 32 {
+		b = b[:32]
+	}
+	return string(b)
+}
+
+// escape escapes the characters RFC 5424 requires in a PARAM-VALUE.
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+}
+
+// Logger is the set of level methods of *log/syslog.Writer used by Writer.
+type Logger interface {
+	Emerg(m string) error
+	Alert(m string) error
+	Crit(m string) error
+	Err(m string) error
+	Warning(m string) error
+	Notice(m string) error
+	Info(m string) error
+	Debug(m string) error
+}
+
+// Writer writes errors to a syslog Logger at the level given by LevelOf.
+type Writer struct {
+	logger Logger
+}
+
+// NewWriter creates a Writer logging to logger.
+func NewWriter(logger Logger) *Writer {
+	return &Writer{logger: logger}
+}
+
+// Log writes err as its structured data followed by its message, the layout of
+// the STRUCTURED-DATA and MSG parts of an RFC 5424 entry. It does nothing when err
+// is nil.
+func (w *Writer) Log(err error) error {
+	if err == nil {
+		return nil
+	}
+	m := StructuredData(err) + " " + err.Error()
+	switch LevelOf(err) {
+	case LevelEmergency:
+		return w.logger.Emerg(m)
+	case LevelAlert:
+		return w.logger.Alert(m)
+	case LevelCritical:
+		return w.logger.Crit(m)
+	case LevelWarning:
+		return w.logger.Warning(m)
+	case LevelNotice:
+		return w.logger.Notice(m)
+	case LevelInfo:
+		return w.logger.Info(m)
+	case LevelDebug:
+		return w.logger.Debug(m)
+	}
+	return w.logger.Err(m)
+}
@@ -0,0 +1,111 @@
+package exceptionsyslog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/tae2089/exception"
+)
+
+// recordingLogger records the level method called and its message.
+type recordingLogger struct {
+	level   string
+	message string
+}
+
+func (l *recordingLogger) log(level, m string) error {
+	l.level, l.message = level, m
+	return nil
+}
+
+func (l *recordingLogger) Emerg(m string) error   { return l.log("emerg", m) }
+func (l *recordingLogger) Alert(m string) error   { return l.log("alert", m) }
+func (l *recordingLogger) Crit(m string) error    { return l.log("crit", m) }
+func (l *recordingLogger) Err(m string) error     { return l.log("err", m) }
+func (l *recordingLogger) Warning(m string) error { return l.log("warning", m) }
+func (l *recordingLogger) Notice(m string) error  { return l.log("notice", m) }
+func (l *recordingLogger) Info(m string) error    { return l.log("info", m) }
+func (l *recordingLogger) Debug(m string) error   { return l.log("debug", m) }
+
+func TestLevelOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Level
+	}{
+		{"plain error", io.EOF, LevelError},
+		{"client error", exception.New("missing", exception.ErrorNotFound), LevelWarning},
+		{"server error", exception.New("boom", exception.ErrorInternalServer), LevelError},
+		{"critical", exception.New("boom", exception.ErrorInternalServer, exception.WithSeverity(exception.SeverityCritical)), LevelCritical},
+		{"info", exception.New("boom", exception.ErrorInternalServer, exception.WithSeverity(exception.SeverityInfo)), LevelInfo},
+		{"debug", exception.New("boom", exception.ErrorInternalServer, exception.WithSeverity(exception.SeverityDebug)), LevelDebug},
+	}
+	for _, tt := range tests {
+		if got := LevelOf(tt.err); got != tt.want {
+			t.Errorf("LevelOf(%s) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStructuredData(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{"plain error", io.EOF, []string{"-"}},
+		{"custom error", exception.New("boom", exception.ErrorNotFound, exception.WithID("e-1"), exception.WithDomain("user")),
+			[]string{`[exception@32473 id="e-1" code="404" domain="user" fingerprint="`}},
+		{"fields sorted", exception.New("boom", exception.ErrorNotFound, exception.WithFields(map[string]any{"b": 2, "a": 1})),
+			[]string{`a="1" b="2"]`}},
+		{"escaped value", exception.New("boom", exception.ErrorNotFound, exception.WithField("q", `a"b]c\d`)),
+			[]string{`q="a\"b\]c\\d"`}},
+		{"invalid name", exception.New("boom", exception.ErrorNotFound, exception.WithField("user id="+strings.Repeat("x", 40), 1)),
+			[]string{` user_id_` + strings.Repeat("x", 24) + `="1"`}},
+		{"fmt wrapped", fmt.Errorf("load: %w", exception.New("boom", exception.ErrorNotFound, exception.WithID("e-2"))),
+			[]string{`id="e-2"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := StructuredData(tt.err)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Fatalf("StructuredData = %s, want it to contain %s", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		level string
+	}{
+		{"client error", exception.New("missing", exception.ErrorNotFound), "warning"},
+		{"server error", exception.New("boom", exception.ErrorInternalServer), "err"},
+		{"critical", exception.New("boom", exception.ErrorInternalServer, exception.WithSeverity(exception.SeverityCritical)), "crit"},
+		{"info", exception.New("boom", exception.ErrorInternalServer, exception.WithSeverity(exception.SeverityInfo)), "info"},
+		{"debug", exception.New("boom", exception.ErrorInternalServer, exception.WithSeverity(exception.SeverityDebug)), "debug"},
+		{"plain error", errors.New("disk full"), "err"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &recordingLogger{}
+			if err := NewWriter(logger).Log(tt.err); err != nil {
+				t.Fatal(err)
+			}
+			if logger.level != tt.level || logger.message != StructuredData(tt.err)+" "+tt.err.Error() {
+				t.Fatalf("logged %q at %s", logger.message, logger.level)
+			}
+		})
+	}
+	logger := &recordingLogger{}
+	if err := NewWriter(logger).Log(nil); err != nil || logger.level != "" {
+		t.Fatal("Log(nil) should not write")
+	}
+}
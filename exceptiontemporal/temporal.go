@@ -0,0 +1,112 @@
+// Package exceptiontemporal converts between exception.CustomError and Temporal
+// application errors so codes and retry semantics survive activity and workflow
+// boundaries.
+package exceptiontemporal
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tae2089/exception"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// ToApplicationError converts the first CustomError in err's chain to a Temporal
+// ApplicationError. The error type is the code name and the details are the
+// fields followed by the code. Errors exception.IsRetryable does not report as
+// retryable are marked non-retryable. Errors whose CustomError is already wrapped
+// in an ApplicationError, and errors without a CustomError, are returned unchanged.
+func ToApplicationError(err error) error {
+	var customErr *exception.CustomError
+	if !errors.As(err, &customErr) || customErr == nil {
+		return err
+	}
+	var appErr *temporal.ApplicationError
+	if errors.As(err, &appErr) {
+		// 바깥의 ApplicationError가 이미 CustomError를 감싸고 있으면 그대로 전달
+		var inner *exception.CustomError
+		if errors.As(appErr, &inner) && inner == customErr {
+			return err
+		}
+	}
+	fields := customErr.Fields()
+	if fields == nil {
+		fields = map[string]any{}
+	}
+	return temporal.NewApplicationErrorWithOptions(customErr.Error(), customErr.Code().Name(), temporal.ApplicationErrorOptions{
+		NonRetryable: !exception.IsRetryable(customErr),
+		Cause:        customErr.Cause(),
+		Details:      []any{fields, int(customErr.Code())},
+	})
+}
+
+// FromApplicationError rebuilds a remote CustomError from the Temporal
+// ApplicationError in err's chain, such as the cause of an ActivityError. It
+// returns nil when err contains no ApplicationError.
+func FromApplicationError(err error) *exception.CustomError {
+	var appErr *temporal.ApplicationError
+	if !errors.As(err, &appErr) {
+		return nil
+	}
+	var (
+		fields map[string]any
+		code   int
+	)
+	if appErr.HasDetails() {
+		_ = appErr.Details(&fields, &code)
+	}
+	if code == 0 {
+		code = int(exception.ErrorInternalServer)
+	}
+	opts := []exception.CustomErrorOption{
+		exception.WithRemote(),
+		exception.WithFields(fields),
+		exception.WithRetryable(!appErr.NonRetryable()),
+	}
+	if cause := errors.Unwrap(appErr); cause != nil {
+		opts = append(opts, exception.WithCause(cause))
+	}
+	return exception.New(appErr.Message(), exception.ErrorCode(code), opts...)
+}
+
+// NewWorkerInterceptor returns a worker interceptor converting CustomErrors
+// returned by activities and workflows with ToApplicationError.
+func NewWorkerInterceptor() interceptor.WorkerInterceptor {
+	return &workerInterceptor{}
+}
+
+type workerInterceptor struct {
+	interceptor.WorkerInterceptorBase
+}
+
+func (w *workerInterceptor) InterceptActivity(ctx context.Context, next interceptor.ActivityInboundInterceptor) interceptor.ActivityInboundInterceptor {
+	i := &activityInbound{}
+	i.Next = next
+	return i
+}
+
+func (w *workerInterceptor) InterceptWorkflow(ctx workflow.Context, next interceptor.WorkflowInboundInterceptor) interceptor.WorkflowInboundInterceptor {
+	i := &workflowInbound{}
+	i.Next = next
+	return i
+}
+
+type activityInbound struct {
+	interceptor.ActivityInboundInterceptorBase
+}
+
+func (a *activityInbound) ExecuteActivity(ctx context.Context, in *interceptor.ExecuteActivityInput) (any, error) {
+	result, err := a.Next.ExecuteActivity(ctx, in)
+	return result, ToApplicationError(err)
+}
+
+type workflowInbound struct {
+	interceptor.WorkflowInboundInterceptorBase
+}
+
+func (w *workflowInbound) ExecuteWorkflow(ctx workflow.Context, in *interceptor.ExecuteWorkflowInput) (any, error) {
+	result, err := w.Next.ExecuteWorkflow(ctx, in)
+	return result, ToApplicationError(err)
+}
@@ -0,0 +1,136 @@
+package exceptiontemporal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/tae2089/exception"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/temporal"
+)
+
+func TestToApplicationError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		errType      string
+		nonRetryable bool
+	}{
+		{"client error", exception.New("bad input", exception.ErrorDataInvalid), "BadRequest", true},
+		{"retryable client error", exception.New("slow down", exception.ErrorCode(429)), "TooManyRequests", false},
+		{"explicitly retryable client error", exception.New("bad input", exception.ErrorDataInvalid, exception.WithRetryable(true)), "BadRequest", false},
+		{"server error", exception.New("db down", exception.ErrorInternalServer), "InternalServerError", true},
+		{"explicitly retryable server error", exception.New("db down", exception.ErrorInternalServer, exception.WithRetryable(true)), "InternalServerError", false},
+		{"explicitly non-retryable server error", exception.New("db down", exception.ErrorCode(503), exception.WithRetryable(false)), "ServiceUnavailable", true},
+		{"unavailable", exception.New("upstream down", exception.ErrorCode(503)), "ServiceUnavailable", false},
+		{"wrapping an application error", exception.Wrap(temporal.NewApplicationError("boom", "Custom"), exception.WithCode(exception.ErrorDataInvalid)), "BadRequest", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var appErr *temporal.ApplicationError
+			if !errors.As(ToApplicationError(tt.err), &appErr) {
+				t.Fatal("ToApplicationError should return an ApplicationError")
+			}
+			if appErr.Type() != tt.errType || appErr.NonRetryable() != tt.nonRetryable {
+				t.Fatalf("type = %q, non-retryable = %v, want %q, %v", appErr.Type(), appErr.NonRetryable(), tt.errType, tt.nonRetryable)
+			}
+		})
+	}
+}
+
+func TestToApplicationErrorKeepsOtherErrors(t *testing.T) {
+	appErr := temporal.NewApplicationError("boom", "Custom")
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"nil", nil},
+		{"plain error", io.EOF},
+		{"application error", appErr},
+		{"application error wrapping a custom error", temporal.NewApplicationErrorWithCause("boom", "Custom", exception.New("bad input", exception.ErrorDataInvalid))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToApplicationError(tt.err); got != tt.err {
+				t.Fatalf("ToApplicationError = %v, want the error unchanged", got)
+			}
+		})
+	}
+}
+
+func TestApplicationErrorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		code      exception.ErrorCode
+		retryable bool
+	}{
+		{"client error", exception.New("bad input", exception.ErrorDataInvalid, exception.WithField("user", "42")), exception.ErrorDataInvalid, false},
+		{"server error", exception.New("db down", exception.ErrorInternalServer, exception.WithField("user", "42")), exception.ErrorInternalServer, false},
+		{"unavailable", exception.New("upstream down", exception.ErrorServiceUnavailable, exception.WithField("user", "42")), exception.ErrorServiceUnavailable, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromApplicationError(ToApplicationError(tt.err))
+			if got == nil {
+				t.Fatal("FromApplicationError should rebuild the error")
+			}
+			if !got.IsRemote() || got.Code() != tt.code || got.Error() != tt.err.Error() {
+				t.Fatalf("FromApplicationError = %+v", got)
+			}
+			if v, _ := got.Field("user"); v != "42" {
+				t.Fatalf("field user = %v, want 42", v)
+			}
+			if exception.IsRetryable(got) != tt.retryable {
+				t.Fatalf("IsRetryable = %v, want %v", exception.IsRetryable(got), tt.retryable)
+			}
+		})
+	}
+	if got := FromApplicationError(io.EOF); got != nil {
+		t.Fatalf("FromApplicationError(io.EOF) = %+v, want nil", got)
+	}
+	if got := FromApplicationError(temporal.NewApplicationError("boom", "Custom")); got.Code() != exception.ErrorInternalServer {
+		t.Fatalf("code without details = %d, want %d", got.Code(), exception.ErrorInternalServer)
+	}
+}
+
+type activityFunc func(ctx context.Context, in *interceptor.ExecuteActivityInput) (any, error)
+
+type fakeActivityInbound struct {
+	interceptor.ActivityInboundInterceptor
+	execute activityFunc
+}
+
+func (f *fakeActivityInbound) ExecuteActivity(ctx context.Context, in *interceptor.ExecuteActivityInput) (any, error) {
+	return f.execute(ctx, in)
+}
+
+func TestActivityInterceptor(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		app  bool
+	}{
+		{"custom error", exception.New("bad input", exception.ErrorDataInvalid), true},
+		{"plain error", io.EOF, false},
+		{"no error", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &fakeActivityInbound{execute: func(context.Context, *interceptor.ExecuteActivityInput) (any, error) {
+				return "ok", tt.err
+			}}
+			inbound := NewWorkerInterceptor().InterceptActivity(context.Background(), next)
+			result, err := inbound.ExecuteActivity(context.Background(), &interceptor.ExecuteActivityInput{})
+			if result != "ok" {
+				t.Fatalf("result = %v, want ok", result)
+			}
+			var appErr *temporal.ApplicationError
+			if errors.As(err, &appErr) != tt.app || (!tt.app && err != tt.err) {
+				t.Fatalf("err = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/tae2089/exception/exceptiontwirp
+
+go 1.24.5
+
+require (
+	github.com/tae2089/exception v0.1.0
+	github.com/twitchtv/twirp v8.1.3+incompatible
+)
+
+require github.com/pkg/errors v0.9.1 // indirect
+
+replace github.com/tae2089/exception => ../
@@ -0,0 +1,162 @@
+// Package exceptiontwirp converts between exception.CustomError and twirp.Error.
+package exceptiontwirp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tae2089/exception"
+	"github.com/twitchtv/twirp"
+)
+
+// Meta keys carrying the error ID and domain.
+const (
+	MetaErrorID     = "error_id"
+	MetaErrorDomain = "error_domain"
+)
+
+var exposeMessages bool
+
+// SetExposeMessages sets whether errors sent to clients without a public message
+// carry their error message, which may include internal details. By default the
+// twirp code is sent instead, as exceptiongrpc does. Enable it only between
+// trusted services. It should be called during initialization.
+func SetExposeMessages(enabled bool) {
+	exposeMessages = enabled
+}
+
+// CodeToTwirp maps an ErrorCode to the twirp error code closest to its HTTP
+// status, which may be registered in the catalog; see exception.HTTPStatus.
+func CodeToTwirp(code exception.ErrorCode) twirp.ErrorCode {
+	if code == 0 {
+		return twirp.Unknown
+	}
+	status := exception.HTTPStatus(code)
+	switch status {
+	case 400:
+		return twirp.InvalidArgument
+	case 401:
+		return twirp.Unauthenticated
+	case 403:
+		return twirp.PermissionDenied
+	case 404:
+		return twirp.NotFound
+	case 408:
+		return twirp.DeadlineExceeded
+	case 409:
+		return twirp.AlreadyExists
+	case 412:
+		return twirp.FailedPrecondition
+	case 429:
+		return twirp.ResourceExhausted
+	case 499:
+		return twirp.Canceled
+	case 501:
+		return twirp.Unimplemented
+	case 503:
+		return twirp.Unavailable
+	case 504:
+		return twirp.DeadlineExceeded
+	}
+	switch {
+	case status >= 400 && status < 500:
+		return twirp.InvalidArgument
+	case status >= 500:
+		return twirp.Internal
+	}
+	return twirp.Unknown
+}
+
+// CodeFromTwirp maps a twirp error code to an ErrorCode.
+func CodeFromTwirp(code twirp.ErrorCode) exception.ErrorCode {
+	switch code {
+	case twirp.InvalidArgument, twirp.OutOfRange, twirp.Malformed:
+		return exception.ErrorInvalidRequest
+	case twirp.AlreadyExists, twirp.Aborted:
+		return 409
+	case twirp.Canceled:
+		return 499
+	}
+	return exception.ErrorCode(twirp.ServerHTTPStatusFromErrorCode(code))
+}
+
+// ToTwirp converts err to a twirp.Error wrapping err, so server hooks and
+// middleware can still reach it with errors.As. The message is the public
+// message of the first CustomError in the chain or, without one, the twirp code
+// unless SetExposeMessages is enabled. Fields become meta values; traces are not
+// exposed. A twirp error in the chain is returned unchanged unless a local
+// CustomError wraps it, in which case the CustomError's code and message win.
+func ToTwirp(err error) twirp.Error {
+	if err == nil {
+		return nil
+	}
+	var customErr *exception.CustomError
+	found := errors.As(err, &customErr) && customErr != nil
+	var twerr twirp.Error
+	if errors.As(err, &twerr) {
+		// 원격 에러이거나 twirp 에러 안쪽에 있는 CustomError는 twirp 에러를 그대로 전달
+		var inner *exception.CustomError
+		if !found || customErr.IsRemote() || errors.As(twerr, &inner) && inner == customErr {
+			return twerr
+		}
+	}
+	if !found {
+		if exposeMessages {
+			return twirp.InternalErrorWith(err)
+		}
+		return twirp.WrapError(twirp.InternalError(string(twirp.Internal)), err)
+	}
+	msg := customErr.PublicMessage()
+	if msg == "" && exposeMessages {
+		msg = customErr.Error()
+	}
+	code := CodeToTwirp(customErr.Code())
+	if msg == "" {
+		msg = string(code)
+	}
+	twerr = twirp.WrapError(twirp.NewError(code, msg), err)
+	for k, v := range customErr.Fields() {
+		twerr = twerr.WithMeta(k, fmt.Sprint(v))
+	}
+	if id := customErr.ID(); id != "" {
+		twerr = twerr.WithMeta(MetaErrorID, id)
+	}
+	if domain := customErr.Domain(); domain != "" {
+		twerr = twerr.WithMeta(MetaErrorDomain, domain)
+	}
+	return twerr
+}
+
+// FromTwirp rebuilds a remote CustomError from a twirp.Error received by a client.
+func FromTwirp(twerr twirp.Error) *exception.CustomError {
+	if twerr == nil {
+		return nil
+	}
+	opts := []exception.CustomErrorOption{exception.WithRemote()}
+	for k, v := range twerr.MetaMap() {
+		switch k {
+		case MetaErrorID:
+			opts = append(opts, exception.WithID(v))
+		case MetaErrorDomain:
+			opts = append(opts, exception.WithDomain(v))
+		default:
+			opts = append(opts, exception.WithField(k, v))
+		}
+	}
+	return exception.New(twerr.Msg(), CodeFromTwirp(twerr.Code()), opts...)
+}
+
+// ServerInterceptor converts errors returned by Twirp handlers to twirp errors
+// with ToTwirp, so services can return CustomErrors directly.
+func ServerInterceptor() twirp.Interceptor {
+	return func(next twirp.Method) twirp.Method {
+		return func(ctx context.Context, req any) (any, error) {
+			resp, err := next(ctx, req)
+			if err != nil {
+				return resp, ToTwirp(err)
+			}
+			return resp, nil
+		}
+	}
+}
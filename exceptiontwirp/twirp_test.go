@@ -0,0 +1,157 @@
+package exceptiontwirp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/tae2089/exception"
+	"github.com/twitchtv/twirp"
+)
+
+func TestCodeMapping(t *testing.T) {
+	tests := []struct {
+		code  exception.ErrorCode
+		twirp twirp.ErrorCode
+		back  exception.ErrorCode
+	}{
+		{400, twirp.InvalidArgument, 400},
+		{401, twirp.Unauthenticated, 401},
+		{403, twirp.PermissionDenied, 403},
+		{404, twirp.NotFound, 404},
+		{409, twirp.AlreadyExists, 409},
+		{418, twirp.InvalidArgument, 400},
+		{429, twirp.ResourceExhausted, 429},
+		{499, twirp.Canceled, 499},
+		{500, twirp.Internal, 500},
+		{503, twirp.Unavailable, 503},
+		{504, twirp.DeadlineExceeded, 408},
+		{0, twirp.Unknown, 500},
+	}
+	for _, tt := range tests {
+		if got := CodeToTwirp(tt.code); got != tt.twirp {
+			t.Fatalf("CodeToTwirp(%d) = %s, want %s", tt.code, got, tt.twirp)
+		}
+		if got := CodeFromTwirp(tt.twirp); got != tt.back {
+			t.Fatalf("CodeFromTwirp(%s) = %d, want %d", tt.twirp, got, tt.back)
+		}
+	}
+}
+
+func TestToTwirpAndBack(t *testing.T) {
+	err := exception.New("user 42 missing", exception.ErrorUserNotFound,
+		exception.WithPublicMessage("user not found"), exception.WithField("user", 42), exception.WithDomain("users"))
+	twerr := ToTwirp(err)
+	if twerr.Code() != twirp.NotFound || twerr.Msg() != "user not found" {
+		t.Fatalf("ToTwirp = %s %q", twerr.Code(), twerr.Msg())
+	}
+	if twerr.Meta("user") != "42" || twerr.Meta(MetaErrorID) != err.ID() || twerr.Meta(MetaErrorDomain) != "users" {
+		t.Fatalf("meta = %v", twerr.MetaMap())
+	}
+
+	back := FromTwirp(twerr)
+	if !back.IsRemote() || back.Code() != exception.ErrorUserNotFound || back.ID() != err.ID() || back.Domain() != "users" {
+		t.Fatalf("FromTwirp = %+v", back)
+	}
+	if v, _ := back.Field("user"); v != "42" {
+		t.Fatalf("field user = %v", v)
+	}
+	if back.Trace != "" {
+		t.Fatal("traces should not cross the wire")
+	}
+}
+
+func TestToTwirpOtherErrors(t *testing.T) {
+	existing := twirp.NotFoundError("missing")
+	tests := []struct {
+		name string
+		err  error
+		code twirp.ErrorCode
+		msg  string
+	}{
+		{"twirp error", existing, twirp.NotFound, "missing"},
+		{"wrapped twirp error", exception.Wrap(existing, exception.WithCode(exception.ErrorConflict), exception.WithPublicMessage("taken")), twirp.AlreadyExists, "taken"},
+		{"plain error", errors.New("db: boom"), twirp.Internal, "internal"},
+		{"message without public message", exception.New("boom", exception.ErrorInternalServer), twirp.Internal, "internal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			twerr := ToTwirp(tt.err)
+			if twerr.Code() != tt.code || twerr.Msg() != tt.msg {
+				t.Fatalf("ToTwirp = %s %q", twerr.Code(), twerr.Msg())
+			}
+		})
+	}
+	if ToTwirp(nil) != nil || FromTwirp(nil) != nil {
+		t.Fatal("nil should convert to nil")
+	}
+}
+
+func TestToTwirpWrapsError(t *testing.T) {
+	notFound := exception.New("user 42 missing", exception.ErrorNotFound)
+	plain := errors.New("db: boom")
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"custom error", fmt.Errorf("lookup: %w", notFound)},
+		{"plain error", plain},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 서버 훅에서 원래 에러를 찾을 수 있어야 함
+			twerr := ToTwirp(tt.err)
+			if !errors.Is(twerr, tt.err) {
+				t.Fatalf("ToTwirp(%v) does not wrap it", tt.err)
+			}
+		})
+	}
+	var customErr *exception.CustomError
+	if !errors.As(ToTwirp(notFound), &customErr) || customErr != notFound {
+		t.Fatalf("errors.As found %v, want the CustomError", customErr)
+	}
+}
+
+func TestCodeToTwirpFromCatalog(t *testing.T) {
+	exception.RegisterCode(exception.CodeInfo{Code: 40401, Name: "OrderMissing", HTTPStatus: 404})
+	if got := CodeToTwirp(40401); got != twirp.NotFound {
+		t.Fatalf("CodeToTwirp(40401) = %s, want %s", got, twirp.NotFound)
+	}
+}
+
+func TestSetExposeMessages(t *testing.T) {
+	SetExposeMessages(true)
+	t.Cleanup(func() { SetExposeMessages(false) })
+	tests := []struct {
+		name string
+		err  error
+		msg  string
+	}{
+		{"plain error", errors.New("db: boom"), "db: boom"},
+		{"message without public message", exception.New("boom", exception.ErrorInternalServer), "boom"},
+		{"public message", exception.New("boom", exception.ErrorInternalServer, exception.WithPublicMessage("try again")), "try again"},
+	}
+	for _, tt := range tests {
+		if twerr := ToTwirp(tt.err); twerr.Msg() != tt.msg {
+			t.Errorf("%s: Msg = %q, want %q", tt.name, twerr.Msg(), tt.msg)
+		}
+	}
+}
+
+func TestServerInterceptor(t *testing.T) {
+	method := ServerInterceptor()(func(ctx context.Context, req any) (any, error) {
+		if req == nil {
+			return nil, exception.New("missing", exception.ErrorDataNotFound)
+		}
+		return req, nil
+	})
+	_, err := method(context.Background(), nil)
+	var twerr twirp.Error
+	if !errors.As(err, &twerr) || twerr.Code() != twirp.NotFound {
+		t.Fatalf("err = %v, want a twirp NotFound error", err)
+	}
+	if resp, err := method(context.Background(), "ok"); resp != "ok" || err != nil {
+		t.Fatalf("method = %v, %v", resp, err)
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/tae2089/exception/exceptionyaml
+
+go 1.24.5
+
+require (
+	github.com/tae2089/exception v0.1.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+replace github.com/tae2089/exception => ../
@@ -0,0 +1,40 @@
+// Package exceptionyaml loads exception catalogs from YAML documents.
+package exceptionyaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/tae2089/exception"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadCatalog reads a YAML catalog document from r and registers it with
+// exception.LoadCatalog. The document has the same structure as the JSON one,
+// which is also valid YAML:
+//
+//	domains: [users]
+//	codes:
+//	  - code: 40401
+//	    name: UserNotFound
+//	    domain: users
+//	    message: user not found
+//	    public_message: No such user.
+//	    http_status: 404
+//	    grpc_code: NOT_FOUND
+//	    help_url: https://example.com/errors/user-not-found
+//	    messages:
+//	      ko: 사용자를 찾을 수 없습니다.
+func LoadCatalog(r io.Reader) error {
+	var doc any
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		return fmt.Errorf("exceptionyaml: invalid catalog document: %w", err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("exceptionyaml: invalid catalog document: %w", err)
+	}
+	return exception.LoadCatalog(bytes.NewReader(data))
+}
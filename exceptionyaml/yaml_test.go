@@ -0,0 +1,54 @@
+package exceptionyaml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tae2089/exception"
+)
+
+func TestLoadCatalog(t *testing.T) {
+	doc := `
+domains: [orders]
+codes:
+  - code: 40471
+    name: OrderNotFound
+    domain: orders
+    message: order not found
+    public_message: No such order.
+    http_status: 404
+    grpc_code: NOT_FOUND
+    help_url: https://example.com/errors/order-not-found
+    messages:
+      ko: 주문을 찾을 수 없습니다.
+`
+	if err := LoadCatalog(strings.NewReader(doc)); err != nil {
+		t.Fatal(err)
+	}
+	want := exception.CodeInfo{Code: 40471, Name: "OrderNotFound", Domain: "orders", Message: "order not found", PublicMessage: "No such order.",
+		HTTPStatus: 404, GRPCCode: "NOT_FOUND", HelpURL: "https://example.com/errors/order-not-found"}
+	if info, ok := exception.LookupCode(40471); !ok || info != want {
+		t.Fatalf("LookupCode = %+v, want %+v", info, want)
+	}
+	if msg, ok := exception.LocalizedMessage(40471, "ko"); !ok || msg != "주문을 찾을 수 없습니다." {
+		t.Fatalf("LocalizedMessage = %q, %v", msg, ok)
+	}
+}
+
+func TestLoadCatalogErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"malformed", "codes: [", "exceptionyaml: invalid catalog document"},
+		{"unknown field", "codes:\n  - code: 40472\n    status: 404\n", "exception: invalid catalog document"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := LoadCatalog(strings.NewReader(tt.doc)); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("LoadCatalog = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package exception
+
+import (
+	"path"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// FrameFilter reports whether a frame should be left out of captured traces.
+type FrameFilter func(frame Frame) bool
+
+var frameFilters []FrameFilter
+
+// SetFrameFilters sets the filters applied when traces are captured, replacing
+// any previous ones. Rejected frames are skipped and the capture continues with
+// the frames above them, so traces show only meaningful application frames. It
+// should be called during initialization.
+func SetFrameFilters(filters ...FrameFilter) {
+	frameFilters = filters
+}
+
+func skipFrame(filters []FrameFilter, frame Frame) bool {
+	for _, filter := range filters {
+		if filter(frame) {
+			return true
+		}
+	}
+	return false
+}
+
+// SkipStdlib rejects frames of the Go runtime and standard library, recognized by
+// a file under GOROOT/src. Module paths without a dot, such as "myapp/internal",
+// are therefore kept.
+func SkipStdlib() FrameFilter {
+	return isStdlibFile
+}
+
+// stdlibRoot returns the GOROOT/src directory of the toolchain the binary was
+// built with, taken from the file of runtime.Callers, with a trailing slash. It is
+// empty when the binary was built with -trimpath.
+var stdlibRoot = sync.OnceValue(func() string {
+	var pcs [1]uintptr
+	runtime.Callers(0, pcs[:])
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+	dir := path.Dir(frame.File)
+	if path.Base(dir) != "runtime" {
+		return ""
+	}
+	if root := path.Dir(dir); root != "." {
+		return root + "/"
+	}
+	return ""
+})
+
+// modulePaths returns the paths of the main module and its dependencies, which
+// prefix their files in binaries built with -trimpath.
+var modulePaths = sync.OnceValue(func() []string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	paths := []string{info.Main.Path}
+	for _, dep := range info.Deps {
+		paths = append(paths, dep.Path)
+	}
+	return paths
+})
+
+func isStdlibFile(frame Frame) bool {
+	if frame.File == "" {
+		return false
+	}
+	if root := stdlibRoot(); root != "" {
+		return strings.HasPrefix(frame.File, root)
+	}
+	// -trimpath: 표준 라이브러리 파일은 "net/http/server.go"처럼 버전 없는 상대 경로
+	if path.IsAbs(frame.File) || strings.Contains(frame.File, "@") {
+		return false
+	}
+	for _, module := range modulePaths() {
+		if module != "" && strings.HasPrefix(frame.File, module+"/") {
+			return false
+		}
+	}
+	return true
+}
+
+// SkipPackages rejects frames of functions whose package path starts with one of
+// the prefixes, such as middleware wrappers.
+func SkipPackages(prefixes ...string) FrameFilter {
+	return func(frame Frame) bool {
+		pkg := framePackage(frame.Function)
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(pkg, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// SkipMatching rejects frames whose formatted "file:line function" matches re.
+func SkipMatching(re *regexp.Regexp) FrameFilter {
+	return func(frame Frame) bool {
+		return re.MatchString(frame.String())
+	}
+}
+
+// framePackage returns the package path of a fully qualified function name such
+// as "github.com/org/repo/pkg.(*T).Method".
+func framePackage(function string) string {
+	slash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[slash+1:], ".")
+	if dot < 0 {
+		return function
+	}
+	return function[:slash+1+dot]
+}
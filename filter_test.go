@@ -0,0 +1,135 @@
+package exception
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSkipStdlib(t *testing.T) {
+	var frames []Frame
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pcs := make([]uintptr, 32)
+		iter := runtime.CallersFrames(pcs[:runtime.Callers(1, pcs)])
+		for {
+			frame, more := iter.Next()
+			frames = append(frames, Frame{File: frame.File, Line: frame.Line, Function: frame.Function})
+			if !more {
+				break
+			}
+		}
+	})
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	skip := SkipStdlib()
+	var sawStdlib bool
+	for _, frame := range frames {
+		stdlib := strings.HasPrefix(frame.Function, "net/http.") || strings.HasPrefix(frame.Function, "runtime.") ||
+			strings.HasPrefix(frame.Function, "testing.")
+		sawStdlib = sawStdlib || stdlib
+		if got := skip(frame); got != stdlib {
+			t.Errorf("SkipStdlib(%s) = %v, want %v", frame, got, stdlib)
+		}
+	}
+	if !sawStdlib {
+		t.Fatal("no standard library frames captured")
+	}
+}
+
+func TestSkipStdlibKeepsDotlessModules(t *testing.T) {
+	skip := SkipStdlib()
+	for _, frame := range []Frame{
+		{File: "/src/myapp/internal/user/service.go", Line: 12, Function: "myapp/internal/user.(*Service).Get"},
+		{File: "/src/app/main.go", Line: 3, Function: "main.main"},
+		{Function: "unknown"},
+	} {
+		if skip(frame) {
+			t.Errorf("SkipStdlib(%s) = true, want false", frame)
+		}
+	}
+}
+
+func TestSkipPackages(t *testing.T) {
+	skip := SkipPackages("github.com/org/middleware", "net/http")
+	tests := []struct {
+		function string
+		want     bool
+	}{
+		{"github.com/org/middleware.Recover.func1", true},
+		{"github.com/org/middleware/auth.(*Checker).ServeHTTP", true},
+		{"net/http.HandlerFunc.ServeHTTP", true},
+		{"github.com/org/app.(*Service).Get", false},
+		{"main.main", false},
+	}
+	for _, tt := range tests {
+		if got := skip(Frame{Function: tt.function}); got != tt.want {
+			t.Errorf("SkipPackages(%q) = %v, want %v", tt.function, got, tt.want)
+		}
+	}
+}
+
+func TestSkipMatching(t *testing.T) {
+	skip := SkipMatching(regexp.MustCompile(`_gen\.go:|\.pb\.`))
+	tests := []struct {
+		frame Frame
+		want  bool
+	}{
+		{Frame{File: "/src/app/models_gen.go", Line: 4, Function: "app.Load"}, true},
+		{Frame{File: "/src/app/api.go", Line: 9, Function: "app/api.pb.(*Client).Get"}, true},
+		{Frame{File: "/src/app/service.go", Line: 12, Function: "app.Get"}, false},
+	}
+	for _, tt := range tests {
+		if got := skip(tt.frame); got != tt.want {
+			t.Errorf("SkipMatching(%s) = %v, want %v", tt.frame, got, tt.want)
+		}
+	}
+}
+
+func TestFramePackage(t *testing.T) {
+	tests := []struct {
+		function string
+		want     string
+	}{
+		{"github.com/org/repo/pkg.(*T).Method", "github.com/org/repo/pkg"},
+		{"github.com/org/repo.Func.func1", "github.com/org/repo"},
+		{"main.main", "main"},
+		{"unknown", "unknown"},
+	}
+	for _, tt := range tests {
+		if got := framePackage(tt.function); got != tt.want {
+			t.Errorf("framePackage(%q) = %q, want %q", tt.function, got, tt.want)
+		}
+	}
+}
+
+func wrapThroughHelper(err error) error {
+	return Wrap(err)
+}
+
+func TestSetFrameFilters(t *testing.T) {
+	helper := func(frame Frame) bool { return strings.HasSuffix(frame.Function, ".wrapThroughHelper") }
+	all := func(Frame) bool { return true }
+	tests := []struct {
+		name    string
+		filters []FrameFilter
+		want    string
+	}{
+		{"no filters", nil, ".wrapThroughHelper"},
+		{"helper skipped", []FrameFilter{helper}, ".TestSetFrameFilters"},
+		{"all skipped keeps the call site", []FrameFilter{all}, ".wrapThroughHelper"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetFrameFilters(tt.filters...)
+			t.Cleanup(func() { SetFrameFilters() })
+			origin := wrapThroughHelper(io.EOF).(*CustomError).Origin()
+			if !strings.HasPrefix(strings.TrimPrefix(origin.Function, "github.com/tae2089/exception"), tt.want) {
+				t.Fatalf("origin = %s, want function %s", origin, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package exception
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+)
+
+// Fingerprint returns a stable identifier grouping errors with the same code
+// raised from the same place: a hash of the code and the origin frame's file and
+// function. Line numbers are left out so that unrelated edits keep the grouping.
+// Errors without a trace are grouped by message instead. It returns "" when err
+// has no CustomError in its chain.
+func Fingerprint(err error) string {
+	var customErr *CustomError
+	if !errors.As(err, &customErr) || customErr == nil {
+		return ""
+	}
+	return customErr.Fingerprint()
+}
+
+// Fingerprint returns the error's fingerprint; see the Fingerprint function.
+func (e *CustomError) Fingerprint() string {
+	if e == nil {
+		return ""
+	}
+	h := sha256.New()
+	h.Write([]byte(strconv.Itoa(int(e.code))))
+	if origin := e.Origin(); !origin.IsZero() {
+		h.Write([]byte{0})
+		h.Write([]byte(origin.File))
+		h.Write([]byte{0})
+		h.Write([]byte(origin.Function))
+	} else {
+		h.Write([]byte{0})
+		h.Write([]byte(e.message()))
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
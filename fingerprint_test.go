@@ -0,0 +1,42 @@
+package exception
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func newFingerprinted(code ErrorCode) error {
+	return Wrap(io.EOF, WithCode(code))
+}
+
+func TestFingerprint(t *testing.T) {
+	first, second := newFingerprinted(ErrorNotFound), newFingerprinted(ErrorNotFound)
+	tests := []struct {
+		name string
+		a, b error
+		same bool
+	}{
+		{"same code and origin", first, second, true},
+		{"wrapped again", first, fmt.Errorf("load: %w", Wrap(second, WithMessage("load user"))), true},
+		{"other code", first, newFingerprinted(ErrorConflict), false},
+		{"other origin", first, Wrap(io.EOF, WithCode(ErrorNotFound)), false},
+		{"untraced, same message", New("boom", ErrorNotFound), New("boom", ErrorNotFound), true},
+		{"untraced, other message", New("boom", ErrorNotFound), New("bang", ErrorNotFound), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := Fingerprint(tt.a), Fingerprint(tt.b)
+			if len(a) != 16 || len(b) != 16 {
+				t.Fatalf("fingerprints %q and %q should be 16 hex digits", a, b)
+			}
+			if (a == b) != tt.same {
+				t.Fatalf("fingerprints %q and %q, want same %v", a, b, tt.same)
+			}
+		})
+	}
+	var nilErr *CustomError
+	if Fingerprint(io.EOF) != "" || nilErr.Fingerprint() != "" {
+		t.Fatal("errors without a CustomError have no fingerprint")
+	}
+}
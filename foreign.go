@@ -0,0 +1,69 @@
+package exception
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+)
+
+// foreignTrace returns the stack recorded by the deepest error in err's chain
+// that exposes a StackTrace() or Stack() method returning program counters, as
+// pkg/errors does, formatted as a trace entry. It returns "" when there is none.
+func foreignTrace(err error) string {
+	var pcs []uintptr
+	for ; err != nil; err = errors.Unwrap(err) {
+		if _, ok := err.(*CustomError); ok {
+			break
+		}
+		if stack := stackOf(err); len(stack) > 0 {
+			pcs = stack
+		}
+	}
+	if len(pcs) == 0 {
+		return ""
+	}
+	filters := frameFilters
+	var frames []Frame
+	for _, pc := range pcs {
+		for _, frame := range symbols.lookup(pc).frames {
+			if !skipFrame(filters, frame) {
+				frames = append(frames, frame)
+			}
+		}
+	}
+	return formatTrace(frames, 1)
+}
+
+var framesType = reflect.TypeFor[[]runtime.Frame]()
+
+// stackOf calls the StackTrace or Stack method of err if it returns a slice of
+// program counters, e.g. pkg/errors.StackTrace, or of runtime.Frame.
+func stackOf(err error) []uintptr {
+	v := reflect.ValueOf(err)
+	for _, name := range []string{"StackTrace", "Stack"} {
+		m := v.MethodByName(name)
+		if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+			continue
+		}
+		out := m.Type().Out(0)
+		switch {
+		case out.Kind() == reflect.Slice && out.Elem().Kind() == reflect.Uintptr:
+			stack := m.Call(nil)[0]
+			pcs := make([]uintptr, stack.Len())
+			for i := range pcs {
+				pcs[i] = uintptr(stack.Index(i).Uint())
+			}
+			return pcs
+		case out == framesType:
+			frames := m.Call(nil)[0].Interface().([]runtime.Frame)
+			pcs := make([]uintptr, 0, len(frames))
+			for _, frame := range frames {
+				if frame.PC != 0 {
+					pcs = append(pcs, frame.PC)
+				}
+			}
+			return pcs
+		}
+	}
+	return nil
+}
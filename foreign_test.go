@@ -0,0 +1,120 @@
+package exception
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// pcFrame and pcStack mirror pkg/errors.Frame and StackTrace: a named slice of
+// program counters.
+type pcFrame uintptr
+
+type pcStack []pcFrame
+
+type pcStackError struct {
+	stack pcStack
+}
+
+func (e *pcStackError) Error() string { return "pc stack" }
+
+func (e *pcStackError) StackTrace() pcStack { return e.stack }
+
+type frameStackError struct {
+	frames []runtime.Frame
+	err    error
+}
+
+func (e *frameStackError) Error() string { return "frame stack" }
+
+func (e *frameStackError) Unwrap() error { return e.err }
+
+func (e *frameStackError) Stack() []runtime.Frame { return e.frames }
+
+// otherStackError has a Stack method that does not return program counters.
+type otherStackError struct{}
+
+func (otherStackError) Error() string { return "other stack" }
+
+func (otherStackError) Stack() string { return "not a stack" }
+
+func newPCStackError() error {
+	pcs := make([]uintptr, 8)
+	pcs = pcs[:runtime.Callers(1, pcs)]
+	stack := make(pcStack, len(pcs))
+	for i, pc := range pcs {
+		stack[i] = pcFrame(pc)
+	}
+	return &pcStackError{stack: stack}
+}
+
+func newFrameStackError(err error) error {
+	pcs := make([]uintptr, 8)
+	iter := runtime.CallersFrames(pcs[:runtime.Callers(1, pcs)])
+	var frames []runtime.Frame
+	for {
+		frame, more := iter.Next()
+		frames = append(frames, frame)
+		if !more {
+			break
+		}
+	}
+	return &frameStackError{frames: frames, err: err}
+}
+
+func TestForeignTrace(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		origin string
+	}{
+		{"no stack", io.EOF, ""},
+		{"other Stack method", otherStackError{}, ""},
+		{"program counters", newPCStackError(), ".newPCStackError"},
+		{"runtime frames", newFrameStackError(io.EOF), ".newFrameStackError"},
+		{"fmt wrapped", fmt.Errorf("load: %w", newPCStackError()), ".newPCStackError"},
+		{"deepest wins", newFrameStackError(newPCStackError()), ".newPCStackError"},
+		{"stops at a CustomError", newFrameStackError(Wrap(newPCStackError())), ".newFrameStackError"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trace := foreignTrace(tt.err)
+			if tt.origin == "" {
+				if trace != "" {
+					t.Fatalf("foreignTrace = %q, want none", trace)
+				}
+				return
+			}
+			if got := parseFrame(trace).Function; !strings.HasSuffix(got, tt.origin) {
+				t.Fatalf("foreign trace starts at %q, want %s", got, tt.origin)
+			}
+		})
+	}
+}
+
+func TestWrapKeepsForeignTrace(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		traces int
+	}{
+		{"plain error", io.EOF, 1},
+		{"error with a stack", newPCStackError(), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customErr := Wrap(tt.err).(*CustomError)
+			if got := len(customErr.TracePoints()); got != tt.traces {
+				t.Fatalf("%d trace points, want %d", got, tt.traces)
+			}
+			if got := parseFrame(customErr.LatestTrace()).Function; !strings.HasPrefix(got, "github.com/tae2089/exception.TestWrapKeepsForeignTrace") {
+				t.Fatalf("latest trace at %q, want the Wrap call", got)
+			}
+			if tt.traces == 2 && !strings.HasSuffix(customErr.Origin().Function, ".newPCStackError") {
+				t.Fatalf("origin = %s, want the foreign stack", customErr.Origin())
+			}
+		})
+	}
+}
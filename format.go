@@ -0,0 +1,42 @@
+package exception
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Format implements fmt.Formatter. %+v writes the message followed by the capture
+// points of every CustomError in the chain, outermost first, each frame as a
+// function line and an indented "file:line" line like the stack traces of
+// github.com/pkg/errors. %#v writes the error's fields; other verbs format the
+// message as a string.
+func (e *CustomError) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		_, _ = io.WriteString(s, e.Error())
+		writeStack(s, e)
+	case verb == 'v' && s.Flag('#') && e != nil:
+		fmt.Fprintf(s, "&%#v", *e)
+	default:
+		fmt.Fprintf(s, fmt.FormatString(s, verb), e.Error())
+	}
+}
+
+func writeStack(w io.Writer, err error) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		customErr, ok := err.(*CustomError)
+		if !ok || customErr == nil {
+			continue
+		}
+		for _, point := range customErr.TracePoints() {
+			for _, frame := range point.Frames {
+				if frame.File == "" {
+					fmt.Fprintf(w, "\n%s", frame.Function)
+					continue
+				}
+				fmt.Fprintf(w, "\n%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+			}
+		}
+	}
+}
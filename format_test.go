@@ -0,0 +1,52 @@
+package exception
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	err := Wrap(io.EOF, WithCode(ErrorNotFound), WithMessage("user missing"))
+	var nilErr *CustomError
+	tests := []struct {
+		format string
+		err    error
+		want   string
+	}{
+		{"%s", err, "user missing"},
+		{"%v", err, "user missing"},
+		{"%q", err, `"user missing"`},
+		{"%14s", err, "  user missing"},
+		{"%v", nilErr, "<nil>"},
+		{"%+v", nilErr, "<nil>"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprintf(tt.format, tt.err); got != tt.want {
+			t.Errorf("Sprintf(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+	if got := fmt.Sprintf("%#v", err); !strings.HasPrefix(got, "&exception.CustomError{") {
+		t.Errorf("Sprintf(%%#v) = %q", got)
+	}
+}
+
+func TestFormatPlusV(t *testing.T) {
+	inner := New("user missing", ErrorNotFound)
+	Wrap(inner)
+	outer := Errorf(ErrorInternalServer, "load: %w", fmt.Errorf("query: %w", inner))
+	got := fmt.Sprintf("%+v", outer)
+	lines := strings.Split(got, "\n")
+	if lines[0] != "load: query: user missing" {
+		t.Fatalf("first line = %q", lines[0])
+	}
+	fn := "github.com/tae2089/exception.TestFormatPlusV"
+	// Errorf가 이어받은 추적 2개와 내부 에러의 추적 1개
+	if n := strings.Count(got, "\n"+fn+"\n\t"); n != 3 {
+		t.Fatalf("%%+v has %d frames of %s, want 3:\n%s", n, fn, got)
+	}
+	if !strings.Contains(got, "format_test.go:") {
+		t.Fatalf("%%+v has no file lines:\n%s", got)
+	}
+}
@@ -0,0 +1,85 @@
+package exception
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// ClientClosed reports whether err means the client of r went away before the
+// response was complete: the handler aborted with http.ErrAbortHandler, or the
+// request context is done and err is context.Canceled or a closed or reset
+// connection. A reset from an upstream service while the request is still live
+// is not the client's doing and is therefore not reported.
+func ClientClosed(r *http.Request, err error) bool {
+	if isNil(err) {
+		return false
+	}
+	if code, ok := CodeOf(err); ok && code == ErrorClientClosedRequest {
+		return true
+	}
+	if errors.Is(err, http.ErrAbortHandler) {
+		return true
+	}
+	if r == nil || r.Context().Err() == nil {
+		return false
+	}
+	return errors.Is(err, context.Canceled) || errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// HandlerFunc is an HTTP handler returning an error, written with
+// WriteHTTPRequest. Errors caused by the client closing the request are recoded
+// as ErrorClientClosedRequest and logged at info level instead, so they are not
+// counted as server errors.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := f(w, r)
+	if isNil(err) {
+		return
+	}
+	if !ClientClosed(r, err) {
+		WriteHTTPRequest(w, r, err)
+		return
+	}
+	err = Wrap(err, WithCode(ErrorClientClosedRequest), WithNoTrace())
+	logClientClosed(r, err)
+	WriteHTTP(w, err)
+}
+
+// Middleware handles handlers of next that abort with http.ErrAbortHandler, as
+// httputil.ReverseProxy does when the client goes away: the abort is logged at
+// info level as ErrorClientClosedRequest and then re-raised so net/http drops the
+// connection as usual.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if p := recover(); p != nil {
+				if err, ok := p.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					logClientClosed(r, New("client closed request", ErrorClientClosedRequest, WithCause(err)))
+				}
+				panic(p)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func logClientClosed(r *http.Request, err error) {
+	var id string
+	if customErr, ok := err.(*CustomError); ok {
+		id = customErr.ID()
+	}
+	logger := httpLogger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.InfoContext(r.Context(), err.Error(),
+		slog.Int("code", int(ErrorClientClosedRequest)),
+		slog.String("id", id),
+	)
+}
@@ -0,0 +1,114 @@
+package exception
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+)
+
+func canceledRequest() *http.Request {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+}
+
+func TestClientClosed(t *testing.T) {
+	live := httptest.NewRequest(http.MethodGet, "/", nil)
+	canceled := canceledRequest()
+	upstreamReset := fmt.Errorf("call upstream: %w", syscall.ECONNRESET)
+
+	tests := []struct {
+		name string
+		r    *http.Request
+		err  error
+		want bool
+	}{
+		{"nil error", canceled, nil, false},
+		{"upstream reset on live request", live, upstreamReset, false},
+		{"broken pipe on live request", live, syscall.EPIPE, false},
+		{"closed conn on live request", live, net.ErrClosed, false},
+		{"canceled on live request", live, context.Canceled, false},
+		{"reset on canceled request", canceled, upstreamReset, true},
+		{"canceled on canceled request", canceled, Wrap(context.Canceled), true},
+		{"abort handler", live, http.ErrAbortHandler, true},
+		{"client closed code", live, New("gone", ErrorClientClosedRequest), true},
+		{"nil request", nil, syscall.ECONNRESET, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClientClosed(tt.r, tt.err); got != tt.want {
+				t.Fatalf("ClientClosed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlerFunc(t *testing.T) {
+	tests := []struct {
+		name   string
+		r      *http.Request
+		err    error
+		status int
+		level  slog.Level
+		logged int
+	}{
+		{"no error", httptest.NewRequest(http.MethodGet, "/", nil), nil, http.StatusOK, 0, 0},
+		{"upstream reset is a server error", httptest.NewRequest(http.MethodGet, "/", nil),
+			Wrap(syscall.ECONNRESET, WithCode(ErrorBadGateway)), http.StatusBadGateway, slog.LevelError, 1},
+		{"client closed", canceledRequest(), fmt.Errorf("query: %w", context.Canceled), int(ErrorClientClosedRequest), slog.LevelInfo, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var records []slog.Record
+			saved := httpLogger
+			SetHTTPLogger(slog.New(recordHandler{records: &records}))
+			t.Cleanup(func() { SetHTTPLogger(saved) })
+			h := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error { return tt.err })
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, tt.r)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if len(records) != tt.logged {
+				t.Fatalf("logged %d records, want %d", len(records), tt.logged)
+			}
+			if tt.logged > 0 && records[0].Level != tt.level {
+				t.Fatalf("logged at %s, want %s", records[0].Level, tt.level)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name   string
+		panic  any
+		logged int
+	}{
+		{"abort handler", http.ErrAbortHandler, 1},
+		{"other panic", "boom", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var records []slog.Record
+			saved := httpLogger
+			SetHTTPLogger(slog.New(recordHandler{records: &records}))
+			t.Cleanup(func() { SetHTTPLogger(saved) })
+			h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { panic(tt.panic) }))
+			defer func() {
+				if p := recover(); p != tt.panic {
+					t.Fatalf("recovered %v, want the panic re-raised", p)
+				}
+				if len(records) != tt.logged || tt.logged == 1 && records[0].Level != slog.LevelInfo {
+					t.Fatalf("logged %v", records)
+				}
+			}()
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+	}
+}
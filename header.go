@@ -0,0 +1,55 @@
+package exception
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// Headers used to correlate errors across service hops.
+const (
+	HeaderErrorID     = "X-Error-Id"
+	HeaderErrorCode   = "X-Error-Code"
+	HeaderErrorDomain = "X-Error-Domain"
+)
+
+// SetHTTPHeaders writes the ID, code and domain of the first CustomError in err's
+// chain to h.
+func SetHTTPHeaders(h http.Header, err error) {
+	var customErr *CustomError
+	if !errors.As(err, &customErr) || customErr == nil {
+		return
+	}
+	if customErr.id != "" {
+		h.Set(HeaderErrorID, customErr.id)
+	}
+	if customErr.code != 0 {
+		h.Set(HeaderErrorCode, strconv.Itoa(int(customErr.code)))
+	}
+	if customErr.domain != "" {
+		h.Set(HeaderErrorDomain, customErr.domain)
+	}
+}
+
+// FromHTTPHeaders rebuilds the remote error described by headers written with
+// SetHTTPHeaders, so it can be used as the cause of a local error. It returns nil
+// when h carries no error headers.
+func FromHTTPHeaders(h http.Header) *CustomError {
+	return FromPropagation(h.Get(HeaderErrorID), h.Get(HeaderErrorCode), h.Get(HeaderErrorDomain))
+}
+
+// FromPropagation rebuilds a remote error from propagated ID, code and domain
+// values. It returns nil when all of them are empty.
+func FromPropagation(id, code, domain string) *CustomError {
+	if id == "" && code == "" && domain == "" {
+		return nil
+	}
+	e := &CustomError{id: id, domain: domain, remote: true}
+	if n, err := strconv.Atoi(code); err == nil {
+		e.code = ErrorCode(n)
+	}
+	if sentinel, ok := SentinelFor(e.code); ok {
+		e.Message = sentinel.message()
+	}
+	return e
+}
@@ -0,0 +1,84 @@
+package exception
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestHTTPHeadersRoundTrip(t *testing.T) {
+	resetSentinels(t)
+	RegisterSentinel(ErrorUserNotFound, New("user missing", 0))
+
+	tests := []struct {
+		name    string
+		err     error
+		headers http.Header
+	}{
+		{
+			name:    "all values",
+			err:     fmt.Errorf("handler: %w", Wrap(New("boom", ErrorUserNotFound), WithID("e-1"), WithDomain("billing"))),
+			headers: http.Header{HeaderErrorID: {"e-1"}, HeaderErrorCode: {"404"}, HeaderErrorDomain: {"billing"}},
+		},
+		{
+			name:    "no domain",
+			err:     Wrap(New("boom", ErrorInternalServer), WithID("e-2")),
+			headers: http.Header{HeaderErrorID: {"e-2"}, HeaderErrorCode: {"500"}},
+		},
+		{name: "plain error", err: fmt.Errorf("boom"), headers: http.Header{}},
+		{name: "nil", err: nil, headers: http.Header{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			SetHTTPHeaders(h, tt.err)
+			if fmt.Sprint(h) != fmt.Sprint(tt.headers) {
+				t.Fatalf("headers = %v, want %v", h, tt.headers)
+			}
+			remote := FromHTTPHeaders(h)
+			if len(tt.headers) == 0 {
+				if remote != nil {
+					t.Fatalf("FromHTTPHeaders = %+v, want nil", remote)
+				}
+				return
+			}
+			if !remote.IsRemote() || remote.ID() != h.Get(HeaderErrorID) || remote.Domain() != h.Get(HeaderErrorDomain) ||
+				fmt.Sprint(int(remote.Code())) != h.Get(HeaderErrorCode) {
+				t.Fatalf("FromHTTPHeaders = %+v", remote)
+			}
+		})
+	}
+}
+
+func TestFromPropagation(t *testing.T) {
+	resetSentinels(t)
+	RegisterSentinel(ErrorUserNotFound, New("user missing", 0))
+	if got := FromPropagation("e-1", "404", ""); got.Error() != "user missing" {
+		t.Fatalf("FromPropagation should take the sentinel message: %q", got.Error())
+	}
+	if got := FromPropagation("e-1", "not-a-code", ""); got.Code() != 0 || got.ID() != "e-1" {
+		t.Fatalf("FromPropagation = %+v", got)
+	}
+	if FromPropagation("", "", "") != nil {
+		t.Fatal("FromPropagation without values should be nil")
+	}
+}
+
+func TestErrorIDs(t *testing.T) {
+	a, b := New("a", ErrorInternalServer), New("b", ErrorInternalServer)
+	if a.ID() == "" || a.ID() == b.ID() || len(a.ID()) != 16 {
+		t.Fatalf("IDs = %q, %q", a.ID(), b.ID())
+	}
+	if id := Build("c").Err().(*CustomError).ID(); id == "" {
+		t.Fatal("Build should generate an ID")
+	}
+	resetSentinels(t)
+	sentinel := RegisterSentinel(ErrorUserNotFound, New("user missing", 0))
+	if wrapped := Wrap(sentinel).(*CustomError); wrapped.ID() == sentinel.ID() {
+		t.Fatal("wrapping a sentinel should give the copy its own ID")
+	}
+	var nilErr *CustomError
+	if nilErr.ID() != "" || nilErr.Domain() != "" {
+		t.Fatal("nil CustomError should have no ID or domain")
+	}
+}
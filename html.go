@@ -0,0 +1,135 @@
+package exception
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+)
+
+// HTMLPage is the data an HTML error page template is executed with. Message,
+// Fields and Trace are only set in development mode.
+type HTMLPage struct {
+	Status        int
+	Code          ErrorCode
+	Name          string
+	ID            string
+	PublicMessage string
+	Hint          string
+	HelpURL       string
+	Development   bool
+	Message       string
+	Fields        map[string]any
+	Trace         []TracePoint
+}
+
+// DefaultHTMLTemplate renders the status, code, public message, help link and
+// error ID, and in development mode the message, fields and a syntax-highlighted
+// trace.
+var DefaultHTMLTemplate = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Status}} {{.Name}}</title>
+<style>
+body{font-family:system-ui,sans-serif;margin:3rem auto;max-width:60rem;padding:0 1rem;color:#222}
+h1{font-size:1.5rem}
+.meta{color:#666}
+.hint{background:#f5f5f5;padding:.5rem 1rem}
+table{border-collapse:collapse}
+td{border-top:1px solid #ddd;padding:.25rem .75rem;font-family:monospace}
+pre{background:#1e1e1e;color:#d4d4d4;padding:1rem;overflow-x:auto}
+.fn{color:#dcdcaa}
+.file{color:#9cdcfe}
+.line{color:#b5cea8}
+.count{color:#ce9178}
+</style>
+</head>
+<body>
+<h1>{{.Status}} {{.Name}}</h1>
+<p>{{.PublicMessage}}</p>
+{{with .Hint}}<p class="hint">{{.}}</p>{{end}}
+{{with .HelpURL}}<p><a href="{{.}}">More information</a></p>{{end}}
+<p class="meta">Error code {{printf "%d" .Code}}{{with .ID}} &middot; ID <code>{{.}}</code>{{end}}</p>
+{{if .Development}}
+<h2>Message</h2>
+<pre>{{.Message}}</pre>
+{{with .Fields}}
+<h2>Fields</h2>
+<table>{{range $k, $v := .}}<tr><td>{{$k}}</td><td>{{printf "%v" $v}}</td></tr>{{end}}</table>
+{{end}}
+{{with .Trace}}
+<h2>Trace</h2>
+<pre>{{range .}}{{if gt .Count 1}}<span class="count">wrapped {{.Count}} times</span>
+{{end}}{{range .Frames}}<span class="fn">{{.Function}}</span>{{if .File}}
+	<span class="file">{{.File}}</span>:<span class="line">{{.Line}}</span>{{end}}
+{{end}}{{end}}</pre>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+// HTMLOptions configures an HTMLRenderer.
+type HTMLOptions struct {
+	// Template is executed with an HTMLPage. The default is DefaultHTMLTemplate.
+	Template *template.Template
+	// Development adds the message, fields and trace to pages. It must not be
+	// enabled in production, where they may leak internal details.
+	Development bool
+}
+
+// HTMLRenderer renders errors as HTML error pages, for server-rendered
+// applications.
+type HTMLRenderer struct {
+	opts HTMLOptions
+}
+
+// NewHTMLRenderer creates an HTMLRenderer.
+func NewHTMLRenderer(opts HTMLOptions) *HTMLRenderer {
+	if opts.Template == nil {
+		opts.Template = DefaultHTMLTemplate
+	}
+	return &HTMLRenderer{opts: opts}
+}
+
+// Page returns the template data for e. The public message falls back to the
+// default message of the code, as in the default JSON body.
+func (r *HTMLRenderer) Page(e *CustomError) HTMLPage {
+	page := HTMLPage{
+		Status:        HTTPStatus(e.Code()),
+		Code:          e.Code(),
+		Name:          e.Code().Name(),
+		ID:            e.ID(),
+		PublicMessage: e.PublicMessage(),
+		Hint:          e.Hint(),
+		Development:   r.opts.Development,
+	}
+	if info, ok := LookupCode(e.Code()); ok {
+		page.HelpURL = info.HelpURL
+	}
+	if page.PublicMessage == "" {
+		page.PublicMessage = DefaultMessage(e.Code())
+	}
+	if r.opts.Development {
+		page.Message = e.Error()
+		page.Fields = e.Fields()
+		page.Trace = e.TracePoints()
+	}
+	return page
+}
+
+// Encode renders e as an HTML page. It is a ResponseEncoder, so passing it to
+// SetResponseEncoder makes WriteHTTP and the handlers built on it write HTML.
+func (r *HTMLRenderer) Encode(e *CustomError) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if err := r.opts.Template.Execute(&buf, r.Page(e)); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "text/html; charset=utf-8", nil
+}
+
+// WriteHTTP writes err like the package-level WriteHTTP, rendering the body as an
+// HTML page regardless of the encoder set with SetResponseEncoder.
+func (r *HTMLRenderer) WriteHTTP(w http.ResponseWriter, err error) {
+	writeHTTP(w, err, r.Encode)
+}
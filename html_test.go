@@ -0,0 +1,96 @@
+package exception
+
+import (
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTMLRenderer(t *testing.T) {
+	err := Wrap(io.EOF, WithCode(ErrorNotFound), WithMessage("user 42 missing <script>"),
+		WithPublicMessage("user not found"), WithID("e-1"), WithField("user", 42))
+	tests := []struct {
+		name        string
+		development bool
+		contains    []string
+		excludes    []string
+	}{
+		{"production", false,
+			[]string{"404 NotFound", "user not found", "e-1"},
+			[]string{"user 42 missing", `class="fn"`, "<td>user</td>"}},
+		{"development", true,
+			[]string{"404 NotFound", "user not found", "e-1", "user 42 missing &lt;script&gt;", "<td>user</td><td>42</td>", `class="file"`, "html_test.go"},
+			[]string{"<script>"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			NewHTMLRenderer(HTMLOptions{Development: tt.development}).WriteHTTP(rec, err)
+			if rec.Code != http.StatusNotFound || rec.Header().Get("Content-Type") != "text/html; charset=utf-8" || rec.Header().Get(HeaderErrorID) != "e-1" {
+				t.Fatalf("status = %d, headers = %v", rec.Code, rec.Header())
+			}
+			body := rec.Body.String()
+			for _, s := range tt.contains {
+				if !strings.Contains(body, s) {
+					t.Errorf("page should contain %q:\n%s", s, body)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(body, s) {
+					t.Errorf("page should not contain %q:\n%s", s, body)
+				}
+			}
+		})
+	}
+}
+
+func TestHTMLRendererDefaultMessage(t *testing.T) {
+	err := New("secret internal detail", ErrorInternalServer)
+	tests := []struct {
+		name        string
+		development bool
+		secret      bool
+	}{
+		{"production", false, false},
+		{"development", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			NewHTMLRenderer(HTMLOptions{Development: tt.development}).WriteHTTP(rec, err)
+			body := rec.Body.String()
+			// 공개 메시지가 없으면 기본 메시지를 표시하고 내부 메시지는 개발 모드에서만 표시
+			if !strings.Contains(body, "<p>internal server error</p>") || strings.Contains(body, "secret internal detail") != tt.secret {
+				t.Fatalf("page:\n%s", body)
+			}
+		})
+	}
+}
+
+func TestHTMLRendererTemplate(t *testing.T) {
+	tests := []struct {
+		name        string
+		tmpl        string
+		contentType string
+		body        string
+	}{
+		{"custom", `{{.Code}}|{{.PublicMessage}}|{{.Development}}`, "text/html; charset=utf-8", "409|conflict|false"},
+		// 템플릿 실행에 실패하면 기본 JSON 본문
+		{"failing", `{{.Missing}}`, "application/json", `"code":409`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewHTMLRenderer(HTMLOptions{Template: template.Must(template.New("").Parse(tt.tmpl))})
+			SetResponseEncoder(r.Encode)
+			t.Cleanup(func() { SetResponseEncoder(nil) })
+			rec := httptest.NewRecorder()
+			WriteHTTP(rec, New("conflict", ErrorConflict))
+			if rec.Header().Get("Content-Type") != tt.contentType || !strings.Contains(rec.Body.String(), tt.body) {
+				t.Fatalf("%s: %s", rec.Header().Get("Content-Type"), rec.Body.String())
+			}
+		})
+	}
+}
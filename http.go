@@ -0,0 +1,90 @@
+package exception
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// responseBody is the JSON body written by WriteHTTP. It only holds information
+// that is safe to return to clients.
+type responseBody struct {
+	ID      string    `json:"id,omitempty"`
+	Code    ErrorCode `json:"code"`
+	Domain  string    `json:"domain,omitempty"`
+	Message string    `json:"message"`
+	Hint    string    `json:"hint,omitempty"`
+	HelpURL string    `json:"help_url,omitempty"`
+}
+
+// HTTPStatus returns the HTTP status code for an ErrorCode: the status registered
+// for it in the catalog or, for codes without one, the code itself if it is a
+// valid status and 500 otherwise.
+func HTTPStatus(code ErrorCode) int {
+	if info, ok := LookupCode(code); ok && info.HTTPStatus >= 100 && info.HTTPStatus <= 599 {
+		return info.HTTPStatus
+	}
+	if code < 100 || code > 599 {
+		return http.StatusInternalServerError
+	}
+	return int(code)
+}
+
+// ResponseEncoder renders the body of an error response and returns it with its
+// content type.
+type ResponseEncoder func(err *CustomError) (body []byte, contentType string, encodeErr error)
+
+var responseEncoder ResponseEncoder = encodeResponse
+
+// SetResponseEncoder replaces how WriteHTTP, and the handlers built on it, render
+// error bodies, so an established error envelope can be used. nil restores the
+// default JSON body. It should be called during initialization.
+func SetResponseEncoder(encoder ResponseEncoder) {
+	if encoder == nil {
+		encoder = encodeResponse
+	}
+	responseEncoder = encoder
+}
+
+// encodeResponse renders the default JSON body. Without a public message the
+// default message of the code is written; the message and traces never are.
+func encodeResponse(e *CustomError) ([]byte, string, error) {
+	body := responseBody{
+		ID:      e.id,
+		Code:    e.code,
+		Domain:  e.domain,
+		Message: e.PublicMessage(),
+		Hint:    e.hint,
+	}
+	if info, ok := LookupCode(e.code); ok {
+		body.HelpURL = info.HelpURL
+	}
+	if body.Message == "" {
+		body.Message = DefaultMessage(e.code)
+	}
+	data, err := json.Marshal(body)
+	return append(data, '\n'), "application/json", err
+}
+
+// WriteHTTP writes err as a response with the status derived from the code of the
+// first CustomError in its chain, along with the propagation headers. The body is
+// rendered by the encoder set with SetResponseEncoder, falling back to the default
+// JSON body if it fails.
+func WriteHTTP(w http.ResponseWriter, err error) {
+	writeHTTP(w, err, responseEncoder)
+}
+
+func writeHTTP(w http.ResponseWriter, err error, encoder ResponseEncoder) {
+	var customErr *CustomError
+	if !errors.As(err, &customErr) || customErr == nil {
+		customErr = New("", ErrorInternalServer)
+	}
+	body, contentType, encodeErr := encoder(customErr)
+	if encodeErr != nil {
+		body, contentType, _ = encodeResponse(customErr)
+	}
+	SetHTTPHeaders(w.Header(), customErr)
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(HTTPStatus(customErr.code))
+	_, _ = w.Write(body)
+}
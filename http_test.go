@@ -0,0 +1,134 @@
+package exception
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		code ErrorCode
+		want int
+	}{
+		{ErrorUserNotFound, http.StatusNotFound},
+		{ErrorDataInvalid, http.StatusBadRequest},
+		{ErrorCode(0), http.StatusInternalServerError},
+		{ErrorCode(99), http.StatusInternalServerError},
+		{ErrorCode(600), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := HTTPStatus(tt.code); got != tt.want {
+			t.Errorf("HTTPStatus(%d) = %d, want %d", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestWriteHTTP(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		body   responseBody
+	}{
+		{
+			name:   "public message",
+			err:    New("user 42 missing", ErrorUserNotFound, WithPublicMessage("user not found"), WithID("e-1"), WithDomain("users"), WithHint("check the id")),
+			status: http.StatusNotFound,
+			body:   responseBody{ID: "e-1", Code: ErrorUserNotFound, Domain: "users", Message: "user not found", Hint: "check the id"},
+		},
+		{
+			name:   "default message",
+			err:    New("invalid email", ErrorDataInvalid),
+			status: http.StatusBadRequest,
+			body:   responseBody{Code: ErrorDataInvalid, Message: DefaultMessage(ErrorDataInvalid)},
+		},
+		{
+			name:   "plain error",
+			err:    errors.New("db: connection refused"),
+			status: http.StatusInternalServerError,
+			body:   responseBody{Code: ErrorInternalServer, Message: DefaultMessage(ErrorInternalServer)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			WriteHTTP(rec, tt.err)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Fatalf("Content-Type = %q", ct)
+			}
+			var body responseBody
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.ID == "" || rec.Header().Get(HeaderErrorID) != body.ID {
+				t.Fatalf("%s = %q, want the body ID %q", HeaderErrorID, rec.Header().Get(HeaderErrorID), body.ID)
+			}
+			// 별도로 지정하지 않은 ID는 생성된 값이므로 비교에서 제외
+			if tt.body.ID == "" {
+				body.ID = ""
+			}
+			if body != tt.body {
+				t.Fatalf("body = %+v, want %+v", body, tt.body)
+			}
+		})
+	}
+}
+
+func TestWriteHTTPHidesMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		message string
+	}{
+		{"CustomError", New("query users: dial 10.0.3.7:5432 failed", ErrorInternalServer), "internal server error"},
+		{"wrapped", Wrap(errors.New("dial 10.0.3.7:5432 failed"), WithMessage("query users"), WithCode(ErrorServiceUnavailable)), "service unavailable"},
+		{"plain error", errors.New("query users: dial 10.0.3.7:5432 failed"), "internal server error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			WriteHTTP(rec, tt.err)
+			if body := rec.Body.String(); strings.Contains(body, "10.0.3.7") || !strings.Contains(body, `"message":"`+tt.message+`"`) {
+				t.Fatalf("body = %s", body)
+			}
+		})
+	}
+}
+
+func TestSetResponseEncoder(t *testing.T) {
+	envelope := func(e *CustomError) ([]byte, string, error) {
+		return []byte(`{"error":"` + e.Code().Name() + `"}`), "application/problem+json", nil
+	}
+	failing := func(e *CustomError) ([]byte, string, error) {
+		return nil, "", errors.New("encode failed")
+	}
+	tests := []struct {
+		name        string
+		encoder     ResponseEncoder
+		body        string
+		contentType string
+	}{
+		{"custom envelope", envelope, `{"error":"NotFound"}`, "application/problem+json"},
+		{"failing encoder", failing, `{"code":404,"message":"not found"}` + "\n", "application/json"},
+		{"nil restores the default", nil, `{"code":404,"message":"not found"}` + "\n", "application/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetResponseEncoder(tt.encoder)
+			t.Cleanup(func() { SetResponseEncoder(nil) })
+			rec := httptest.NewRecorder()
+			WriteHTTP(rec, New("user missing", ErrorNotFound, WithID("")))
+			body := rec.Body.String()
+			if body != tt.body || rec.Header().Get("Content-Type") != tt.contentType || rec.Code != http.StatusNotFound {
+				t.Fatalf("response = %d %q %s", rec.Code, rec.Header().Get("Content-Type"), body)
+			}
+		})
+	}
+}
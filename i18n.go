@@ -0,0 +1,163 @@
+package exception
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	localeMu       sync.RWMutex
+	localeCatalogs = make(map[string]map[ErrorCode]string)
+	defaultLocale  = "en"
+	httpLogger     *slog.Logger
+)
+
+// RegisterMessages registers localized public messages per code for a locale such
+// as "ko" or "en-US". Registering a locale again adds to its messages.
+func RegisterMessages(locale string, messages map[ErrorCode]string) {
+	localeMu.Lock()
+	defer localeMu.Unlock()
+	locale = strings.ToLower(locale)
+	catalog, ok := localeCatalogs[locale]
+	if !ok {
+		catalog = make(map[ErrorCode]string, len(messages))
+		localeCatalogs[locale] = catalog
+	}
+	for code, msg := range messages {
+		catalog[code] = msg
+	}
+}
+
+// SetDefaultLocale sets the locale used when no requested locale has messages.
+// The default is "en".
+func SetDefaultLocale(locale string) {
+	localeMu.Lock()
+	defer localeMu.Unlock()
+	defaultLocale = strings.ToLower(locale)
+}
+
+// SetHTTPLogger sets the logger WriteHTTPRequest logs canonical messages to;
+// nil selects slog.Default.
+func SetHTTPLogger(logger *slog.Logger) {
+	httpLogger = logger
+}
+
+// LocalizedMessage returns the message registered for code in locale.
+func LocalizedMessage(code ErrorCode, locale string) (string, bool) {
+	localeMu.RLock()
+	defer localeMu.RUnlock()
+	msg, ok := localeCatalogs[strings.ToLower(locale)][code]
+	return msg, ok
+}
+
+// NegotiateLocale picks the registered locale best matching an Accept-Language
+// header, trying each requested language in order of preference and then its base
+// language, and falls back to the default locale.
+func NegotiateLocale(acceptLanguage string) string {
+	return negotiateLocale(acceptLanguage, func(map[ErrorCode]string) bool { return true })
+}
+
+// negotiateLocaleFor is NegotiateLocale restricted to the locales with a message
+// for code, so a preferred locale lacking it does not hide a later one that has it.
+func negotiateLocaleFor(acceptLanguage string, code ErrorCode) string {
+	return negotiateLocale(acceptLanguage, func(messages map[ErrorCode]string) bool {
+		_, ok := messages[code]
+		return ok
+	})
+}
+
+func negotiateLocale(acceptLanguage string, accept func(messages map[ErrorCode]string) bool) string {
+	localeMu.RLock()
+	defer localeMu.RUnlock()
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		if tag == "*" {
+			break
+		}
+		if messages, ok := localeCatalogs[tag]; ok && accept(messages) {
+			return tag
+		}
+		if base, _, ok := strings.Cut(tag, "-"); ok {
+			if messages, ok := localeCatalogs[base]; ok && accept(messages) {
+				return base
+			}
+		}
+	}
+	return defaultLocale
+}
+
+// parseAcceptLanguage returns the lower-cased language tags of an Accept-Language
+// header ordered by quality.
+func parseAcceptLanguage(header string) []string {
+	type tag struct {
+		name    string
+		quality float64
+	}
+	var tags []tag
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if name == "" {
+			continue
+		}
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = v
+			}
+		}
+		if quality > 0 {
+			tags = append(tags, tag{name: strings.ToLower(name), quality: quality})
+		}
+	}
+	slices.SortStableFunc(tags, func(a, b tag) int {
+		switch {
+		case a.quality > b.quality:
+			return -1
+		case a.quality < b.quality:
+			return 1
+		}
+		return 0
+	})
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.name
+	}
+	return names
+}
+
+// WriteHTTPRequest writes err like WriteHTTP, rendering the public message of the
+// locale negotiated from the request's Accept-Language header among the locales
+// with a message for the code, and logs the canonical message. Without a
+// localized message for the code, the error's own public message or the default
+// message of its code is written. The response varies by Accept-Language.
+func WriteHTTPRequest(w http.ResponseWriter, r *http.Request, err error) {
+	var customErr *CustomError
+	if !errors.As(err, &customErr) || customErr == nil {
+		customErr = New("", ErrorInternalServer, WithCause(err))
+	}
+	locale := negotiateLocaleFor(r.Header.Get("Accept-Language"), customErr.code)
+	logger := httpLogger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	// 일반 에러는 응답용 문구 대신 원래 메시지를 기록
+	msg := customErr.Error()
+	if err != nil {
+		msg = err.Error()
+	}
+	logger.ErrorContext(r.Context(), msg,
+		slog.Int("code", int(customErr.code)),
+		slog.String("id", customErr.id),
+		slog.String("locale", locale),
+	)
+	if msg, ok := LocalizedMessage(customErr.code, locale); ok {
+		customErr = customErr.WithPublic(msg)
+		w.Header().Set("Content-Language", locale)
+	}
+	w.Header().Add("Vary", "Accept-Language")
+	WriteHTTP(w, customErr)
+}
@@ -0,0 +1,179 @@
+package exception
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// resetLocales restores the registered messages and the default locale after the test.
+func resetLocales(t *testing.T) {
+	t.Helper()
+	localeMu.Lock()
+	saved, savedDefault := localeCatalogs, defaultLocale
+	localeCatalogs = make(map[string]map[ErrorCode]string)
+	localeMu.Unlock()
+	t.Cleanup(func() {
+		localeMu.Lock()
+		localeCatalogs, defaultLocale = saved, savedDefault
+		localeMu.Unlock()
+	})
+}
+
+// discardHTTPLog silences WriteHTTPRequest for the duration of the test.
+func discardHTTPLog(t *testing.T) {
+	t.Helper()
+	saved := httpLogger
+	SetHTTPLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	t.Cleanup(func() { SetHTTPLogger(saved) })
+}
+
+// recordHandler keeps the records logged through it.
+type recordHandler struct {
+	records *[]slog.Record
+}
+
+func (h recordHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h recordHandler) Handle(_ context.Context, r slog.Record) error {
+	*h.records = append(*h.records, r)
+	return nil
+}
+
+func (h recordHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h recordHandler) WithGroup(string) slog.Handler { return h }
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"", []string{}},
+		{"ko-KR,ko;q=0.9,en;q=0.8", []string{"ko-kr", "ko", "en"}},
+		{"en;q=0.5, fr", []string{"fr", "en"}},
+		{"de;q=0, fr;q=bad", []string{"fr"}},
+		{" , *", []string{"*"}},
+	}
+	for _, tt := range tests {
+		if got := parseAcceptLanguage(tt.header); !slices.Equal(got, tt.want) {
+			t.Errorf("parseAcceptLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	resetLocales(t)
+	RegisterMessages("ko", map[ErrorCode]string{ErrorNotFound: "찾을 수 없습니다."})
+	RegisterMessages("FR", map[ErrorCode]string{ErrorConflict: "Conflit."})
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"ko-KR,ko;q=0.9", "ko"},
+		{"de, fr;q=0.5, ko;q=1", "ko"},
+		{"fr-CA", "fr"},
+		{"de", "en"},
+		{"*, ko;q=0.5", "en"},
+		{"", "en"},
+	}
+	for _, tt := range tests {
+		if got := NegotiateLocale(tt.header); got != tt.want {
+			t.Errorf("NegotiateLocale(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+	if got := negotiateLocaleFor("ko, fr;q=0.5", ErrorConflict); got != "fr" {
+		t.Errorf("negotiateLocaleFor = %q, want fr", got)
+	}
+	SetDefaultLocale("KO")
+	if got := NegotiateLocale("de"); got != "ko" {
+		t.Errorf("NegotiateLocale with default ko = %q", got)
+	}
+}
+
+func TestWriteHTTPRequestLocalizes(t *testing.T) {
+	resetLocales(t)
+	discardHTTPLog(t)
+	RegisterMessages("ko", map[ErrorCode]string{ErrorNotFound: "찾을 수 없습니다."})
+	RegisterMessages("fr", map[ErrorCode]string{ErrorConflict: "Conflit."})
+	tests := []struct {
+		name     string
+		err      error
+		header   string
+		message  string
+		language string
+	}{
+		{"preferred locale", New("missing", ErrorNotFound), "ko-KR", "찾을 수 없습니다.", "ko"},
+		{"locale with the code", New("conflict", ErrorConflict), "ko, fr;q=0.5", "Conflit.", "fr"},
+		{"no localized message", New("user missing", ErrorUserNotFound), "de", DefaultMessage(ErrorUserNotFound), ""},
+		{"plain error", io.EOF, "ko", DefaultMessage(ErrorInternalServer), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept-Language", tt.header)
+			rec := httptest.NewRecorder()
+			WriteHTTPRequest(rec, r, tt.err)
+			var body struct {
+				Message string `json:"message"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Message != tt.message || rec.Header().Get("Content-Language") != tt.language {
+				t.Fatalf("message %q, Content-Language %q", body.Message, rec.Header().Get("Content-Language"))
+			}
+			if rec.Header().Get("Vary") != "Accept-Language" {
+				t.Fatalf("Vary = %q", rec.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestWriteHTTPRequestLogs(t *testing.T) {
+	resetLocales(t)
+	RegisterMessages("ko", map[ErrorCode]string{ErrorNotFound: "찾을 수 없습니다.", ErrorInternalServer: "서버 오류"})
+	var records []slog.Record
+	saved := httpLogger
+	SetHTTPLogger(slog.New(recordHandler{records: &records}))
+	t.Cleanup(func() { SetHTTPLogger(saved) })
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+		code ErrorCode
+	}{
+		{"custom error", New("user 7 missing", ErrorNotFound), "user 7 missing", ErrorNotFound},
+		{"plain error", io.EOF, "EOF", ErrorInternalServer},
+		{"wrapped custom error", fmt.Errorf("load: %w", New("user 7 missing", ErrorNotFound)), "load: user 7 missing", ErrorNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records = records[:0]
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept-Language", "ko")
+			WriteHTTPRequest(httptest.NewRecorder(), r, tt.err)
+			if len(records) != 1 {
+				t.Fatalf("logged %d records", len(records))
+			}
+			if records[0].Message != tt.want || records[0].Level != slog.LevelError {
+				t.Fatalf("logged %q at %s, want %q", records[0].Message, records[0].Level, tt.want)
+			}
+			attrs := map[string]string{}
+			records[0].Attrs(func(a slog.Attr) bool {
+				attrs[a.Key] = a.Value.String()
+				return true
+			})
+			if attrs["code"] != fmt.Sprint(int(tt.code)) || attrs["locale"] != "ko" || attrs["id"] == "" {
+				t.Fatalf("attrs = %v", attrs)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package exception
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Interner returns shared instances for identical errors created repeatedly at
+// the same call site, such as a tight loop rejecting every item, avoiding the
+// allocation and trace capture of each one. The instances are frozen like
+// sentinels: wrapping them or calling their With* methods works on a copy, so
+// they must not be modified through their exported fields. Interned errors are
+// audited only when first created. An Interner is safe for concurrent use.
+type Interner struct {
+	mu      sync.RWMutex
+	entries map[internKey]*CustomError
+	size    int
+}
+
+type internKey struct {
+	pc   uintptr
+	code ErrorCode
+	msg  string
+}
+
+// NewInterner creates an interner holding at most size distinct errors; once
+// full, further errors are created without interning. A size below 1 selects 1024.
+func NewInterner(size int) *Interner {
+	if size < 1 {
+		size = 1024
+	}
+	return &Interner{entries: make(map[internKey]*CustomError), size: size}
+}
+
+// New returns the shared error with msg and code for the caller's call site,
+// creating it with the caller's trace on first use. Each call increments its
+// Occurrences. Errors from distinct call sites, messages or codes are distinct.
+func (in *Interner) New(msg string, code ErrorCode) *CustomError {
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+	key := internKey{pc: pcs[0], code: code, msg: msg}
+
+	in.mu.RLock()
+	e, ok := in.entries[key]
+	in.mu.RUnlock()
+	if ok {
+		e.occurrences.Add(1)
+		return e
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if e, ok := in.entries[key]; ok {
+		e.occurrences.Add(1)
+		return e
+	}
+	e = newCustomError(WithMessage(msg), WithCode(code))
+	e.pushTrace(captureStackTrace(0, -1)) // wrapError 단계가 없으므로 한 프레임 덜 건너뜀
+	e.finish()
+	if len(in.entries) >= in.size {
+		return e
+	}
+	e.frozen = true
+	e.occurrences = new(atomic.Int64)
+	e.occurrences.Store(1)
+	in.entries[key] = e
+	return e
+}
+
+// Occurrences returns how many times an interned error was returned by its
+// Interner, and 1 for any other error. Copies made by wrapping an interned error
+// or calling its With* methods share its count.
+func (e *CustomError) Occurrences() int64 {
+	if e == nil {
+		return 0
+	}
+	if e.occurrences == nil {
+		return 1
+	}
+	return e.occurrences.Load()
+}
@@ -0,0 +1,140 @@
+package exception
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+// rejectAll interns the error of every item at a single call site.
+func rejectAll(in *Interner, n int) []*CustomError {
+	errs := make([]*CustomError, n)
+	for i := range errs {
+		errs[i] = in.New("item rejected", ErrorBadRequest)
+	}
+	return errs
+}
+
+func TestInterner(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		create func(in *Interner) []*CustomError
+		shared []bool
+		counts []int64
+	}{
+		{"same call site", 8, func(in *Interner) []*CustomError { return rejectAll(in, 3) },
+			[]bool{true, true, true}, []int64{3, 3, 3}},
+		{"distinct call sites", 8, func(in *Interner) []*CustomError {
+			return []*CustomError{in.New("item rejected", ErrorBadRequest), in.New("item rejected", ErrorBadRequest)}
+		}, []bool{true, true}, []int64{1, 1}},
+		{"distinct messages and codes", 8, func(in *Interner) []*CustomError {
+			var errs []*CustomError
+			for _, tc := range []struct {
+				msg  string
+				code ErrorCode
+			}{{"a", ErrorBadRequest}, {"b", ErrorBadRequest}, {"a", ErrorConflict}} {
+				errs = append(errs, in.New(tc.msg, tc.code))
+			}
+			return errs
+		}, []bool{true, true, true}, []int64{1, 1, 1}},
+		{"full", 1, func(in *Interner) []*CustomError {
+			return []*CustomError{in.New("a", ErrorBadRequest), in.New("b", ErrorBadRequest)}
+		}, []bool{true, false}, []int64{1, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.create(NewInterner(tt.size))
+			for i, e := range errs {
+				if e.Shared() != tt.shared[i] || e.Occurrences() != tt.counts[i] {
+					t.Fatalf("error %d: shared %v with %d occurrences, want %v with %d",
+						i, e.Shared(), e.Occurrences(), tt.shared[i], tt.counts[i])
+				}
+				if !strings.HasSuffix(e.Origin().File, "intern_test.go") {
+					t.Fatalf("error %d: Origin = %s, want the caller", i, e.Origin())
+				}
+				// 같은 호출 위치의 에러는 한 인스턴스를 공유
+				if i > 0 && (errs[i-1] == e) != (tt.counts[i] > 1) {
+					t.Fatalf("errors %d and %d: same instance %v", i-1, i, errs[i-1] == e)
+				}
+			}
+		})
+	}
+}
+
+func TestInternedErrorIsCopiedOnWrite(t *testing.T) {
+	in := NewInterner(8)
+	shared := rejectAll(in, 2)[0]
+	tests := []struct {
+		name  string
+		use   func() error
+		newID bool
+	}{
+		{"Wrap", func() error { return Wrap(shared, WithField("item", 7)) }, true},
+		{"WithCodeC", func() error { return shared.WithCodeC(ErrorConflict) }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			copied := tt.use().(*CustomError)
+			if copied == shared || copied.Shared() || copied.Occurrences() != 2 || (copied.ID() != shared.ID()) != tt.newID {
+				t.Fatalf("%s should work on a fresh copy: %+v", tt.name, copied)
+			}
+		})
+	}
+	if shared.Fields() != nil || shared.Code() != ErrorBadRequest || len(shared.PreviousTraces) != 0 || shared.Occurrences() != 2 {
+		t.Fatalf("the interned error was modified: %+v", shared)
+	}
+}
+
+func TestInternedOccurrencesAfterWrap(t *testing.T) {
+	in := NewInterner(8)
+	var wrapped *CustomError
+	for i := range 5 {
+		e := in.New("item rejected", ErrorBadRequest)
+		if i == 2 {
+			wrapped = Wrap(e, WithMessage("import failed")).(*CustomError)
+			if got := wrapped.Occurrences(); got != 3 {
+				t.Fatalf("Occurrences after Wrap = %d, want 3", got)
+			}
+		}
+	}
+	// 복사본도 이후의 발생 횟수를 함께 봄
+	if got := wrapped.Occurrences(); got != 5 {
+		t.Fatalf("Occurrences = %d, want 5", got)
+	}
+}
+
+func TestInternerAuditsOnce(t *testing.T) {
+	events := recordAudits(t, []ErrorCode{ErrorBadRequest}, nil)
+	rejectAll(NewInterner(8), 5)
+	if len(*events) != 1 {
+		t.Fatalf("audited %d times, want once", len(*events))
+	}
+}
+
+func TestInternerConcurrent(t *testing.T) {
+	in := NewInterner(8)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	seen := make(map[*CustomError]struct{})
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, e := range rejectAll(in, 100) {
+				mu.Lock()
+				seen[e] = struct{}{}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 1 {
+		t.Fatalf("interned %d instances, want 1", len(seen))
+	}
+	for e := range seen {
+		if e.Occurrences() != 800 {
+			t.Fatalf("Occurrences = %d, want 800", e.Occurrences())
+		}
+	}
+}
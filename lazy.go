@@ -0,0 +1,58 @@
+package exception
+
+import (
+	"fmt"
+	"sync"
+)
+
+// lazyMessage renders a message from its format and arguments on first use.
+type lazyMessage struct {
+	once   sync.Once
+	format string
+	args   []any
+	msg    string
+	// truncated is set when the rendered message was cut by the limits.
+	truncated bool
+}
+
+func (l *lazyMessage) String() string {
+	l.once.Do(func() {
+		l.msg = fmt.Sprintf(l.format, l.args...)
+		l.args = nil
+		if limits.MaxMessageLength > 0 {
+			l.msg, l.truncated = truncate(l.msg, limits.MaxMessageLength)
+		}
+	})
+	return l.msg
+}
+
+// NewLazy creates a new CustomError whose message is formatted only when it is
+// needed, such as by Error or Encode. The arguments are retained until then, so
+// they must not be modified afterwards.
+func NewLazy(code ErrorCode, format string, args ...any) *CustomError {
+	e := newCustomError(WithCode(code))
+	e.lazy = &lazyMessage{format: format, args: args}
+	e.finish()
+	return e
+}
+
+// WrapLazy wraps err like WrapMessageWithCode with a message formatted only when
+// it is needed.
+func WrapLazy(err error, code ErrorCode, format string, args ...any) error {
+	return wrapError(err, WithCode(code), withLazyMessage(format, args))
+}
+
+func withLazyMessage(format string, args []any) CustomErrorOption {
+	return func(e *CustomError) {
+		e.Message = ""
+		e.lazy = &lazyMessage{format: format, args: args}
+	}
+}
+
+// message returns the error message, rendering a lazy message if needed.
+func (e *CustomError) message() string {
+	if e.Message == "" && e.lazy != nil {
+		return e.lazy.String()
+	}
+	return e.Message
+}
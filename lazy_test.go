@@ -0,0 +1,92 @@
+package exception
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// countingArg counts how often it is formatted.
+type countingArg struct{ calls atomic.Int32 }
+
+func (c *countingArg) String() string {
+	c.calls.Add(1)
+	return "arg"
+}
+
+func TestLazyMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		make func(arg *countingArg) *CustomError
+		want string
+	}{
+		{"new", func(arg *countingArg) *CustomError { return NewLazy(ErrorDataInvalid, "bad %s", arg) }, "bad arg"},
+		{"wrap plain error", func(arg *countingArg) *CustomError {
+			return WrapLazy(io.EOF, ErrorDataInvalid, "read %s", arg).(*CustomError)
+		}, "read arg"},
+		{"wrap replaces message", func(arg *countingArg) *CustomError {
+			return WrapLazy(New("old", ErrorInternalServer), ErrorDataInvalid, "new %s", arg).(*CustomError)
+		}, "new arg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arg := &countingArg{}
+			customErr := tt.make(arg)
+			if n := arg.calls.Load(); n != 0 {
+				t.Fatalf("message formatted %d times before use", n)
+			}
+			if customErr.Code() != ErrorDataInvalid {
+				t.Fatalf("code = %d", customErr.Code())
+			}
+			for range 2 {
+				if got := customErr.Error(); got != tt.want {
+					t.Fatalf("Error() = %q, want %q", got, tt.want)
+				}
+			}
+			if n := arg.calls.Load(); n != 1 {
+				t.Fatalf("message formatted %d times, want once", n)
+			}
+		})
+	}
+}
+
+func TestLazyMessageEncoded(t *testing.T) {
+	data, err := Encode(NewLazy(ErrorDataInvalid, "user %d invalid", 42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Message != "user 42 invalid" {
+		t.Fatalf("decoded message = %q", got.Message)
+	}
+}
+
+func TestLazyMessageConcurrent(t *testing.T) {
+	arg := &countingArg{}
+	customErr := NewLazy(ErrorDataInvalid, "bad %s", arg)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := fmt.Sprint(customErr); got != "bad arg" {
+				t.Errorf("message = %q", got)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := arg.calls.Load(); n != 1 {
+		t.Fatalf("message formatted %d times, want once", n)
+	}
+}
+
+func TestWrapLazyNil(t *testing.T) {
+	if err := WrapLazy(nil, ErrorDataInvalid, "bad %s", "x"); err != nil {
+		t.Fatalf("WrapLazy(nil) = %v", err)
+	}
+}
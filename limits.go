@@ -0,0 +1,120 @@
+package exception
+
+import (
+	"encoding/json"
+	"slices"
+	"unicode/utf8"
+)
+
+// TruncatedMarker is appended to messages and field values cut by the limits.
+const TruncatedMarker = "...(truncated)"
+
+// Limits bounds the size of errors. A zero value disables the corresponding limit.
+type Limits struct {
+	// MaxMessageLength is the maximum length in bytes of messages.
+	MaxMessageLength int
+	// MaxFields is the maximum number of fields; fields beyond it are dropped in key order.
+	MaxFields int
+	// MaxFieldLength is the maximum length in bytes of string and []byte field values.
+	MaxFieldLength int
+	// MaxSerializedSize is the maximum size in bytes written by Encode and
+	// MarshalJSON. Causes, then fields, then traces are dropped to fit, and the
+	// message is cut as a last resort.
+	MaxSerializedSize int
+}
+
+var limits Limits
+
+// SetLimits sets the size limits applied when errors are created or wrapped and
+// when they are serialized. Errors cut by a limit report Truncated. It should be
+// called during initialization.
+func SetLimits(l Limits) {
+	limits = l
+}
+
+// Truncated reports whether the message or fields of the error were cut by the limits.
+func (e *CustomError) Truncated() bool {
+	if e == nil {
+		return false
+	}
+	return e.truncated || e.lazy != nil && e.Message == "" && e.lazy.truncated
+}
+
+// applyLimits cuts the message and fields of the error to the configured limits.
+func (e *CustomError) applyLimits() {
+	l := limits
+	if l.MaxMessageLength > 0 {
+		if msg, cut := truncate(e.Message, l.MaxMessageLength); cut {
+			e.Message, e.truncated = msg, true
+		}
+	}
+	if l.MaxFieldLength > 0 {
+		for k, v := range e.fields {
+			var s string
+			switch v := v.(type) {
+			case string:
+				s = v
+			case []byte:
+				s = string(v)
+			default:
+				continue
+			}
+			if s, cut := truncate(s, l.MaxFieldLength); cut {
+				e.fields[k], e.truncated = s, true
+			}
+		}
+	}
+	if l.MaxFields > 0 && len(e.fields) > l.MaxFields {
+		keys := make([]string, 0, len(e.fields))
+		for k := range e.fields {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys[l.MaxFields:] {
+			delete(e.fields, k)
+		}
+		e.truncated = true
+	}
+}
+
+// truncate cuts s to at most max bytes, including the marker, at a rune boundary.
+func truncate(s string, max int) (string, bool) {
+	if len(s) <= max {
+		return s, false
+	}
+	n := max - len(TruncatedMarker)
+	if n <= 0 {
+		return TruncatedMarker[:max], true
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + TruncatedMarker, true
+}
+
+// marshalLimited marshals w, dropping parts of it until it fits MaxSerializedSize.
+func marshalLimited(w *wireError) ([]byte, error) {
+	data, err := json.Marshal(w)
+	limit := limits.MaxSerializedSize
+	if err != nil || w == nil || limit <= 0 || len(data) <= limit {
+		return data, err
+	}
+	reductions := []func(){
+		func() { w.Cause = nil },
+		func() { w.Fields = nil },
+		func() { w.Traces = nil },
+		func() {
+			over := len(data) - limit
+			w.Message, _ = truncate(w.Message, max(len(w.Message)-over, 0))
+			w.PublicMessage, w.Hint = "", ""
+		},
+	}
+	for _, reduce := range reductions {
+		reduce()
+		w.Truncated = true
+		if data, err = json.Marshal(w); err != nil || len(data) <= limit {
+			return data, err
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,171 @@
+package exception
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func setLimits(t *testing.T, l Limits) {
+	t.Helper()
+	prev := limits
+	SetLimits(l)
+	t.Cleanup(func() { SetLimits(prev) })
+}
+
+func TestMessageLimit(t *testing.T) {
+	long := strings.Repeat("a", 40)
+	tests := []struct {
+		name      string
+		err       func() *CustomError
+		want      string
+		truncated bool
+	}{
+		{"short", func() *CustomError { return New("boom", ErrorInternalServer) }, "boom", false},
+		{"new", func() *CustomError { return New(long, ErrorInternalServer) }, strings.Repeat("a", 6) + TruncatedMarker, true},
+		{"wrap", func() *CustomError { return WrapMessage(io.EOF, long).(*CustomError) }, strings.Repeat("a", 6) + TruncatedMarker, true},
+		{"rune boundary", func() *CustomError { return New("aaaaa"+strings.Repeat("é", 10), ErrorInternalServer) }, "aaaaa" + TruncatedMarker, true},
+		{"lazy", func() *CustomError { return NewLazy(ErrorInternalServer, "%s", long) }, strings.Repeat("a", 6) + TruncatedMarker, true},
+		{"short lazy", func() *CustomError { return NewLazy(ErrorInternalServer, "%s", "boom") }, "boom", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setLimits(t, Limits{MaxMessageLength: 20})
+			customErr := tt.err()
+			if got := customErr.Error(); got != tt.want {
+				t.Fatalf("Error() = %q, want %q", got, tt.want)
+			}
+			if customErr.Truncated() != tt.truncated {
+				t.Fatalf("Truncated = %v, want %v", customErr.Truncated(), tt.truncated)
+			}
+		})
+	}
+}
+
+func TestLazyMessageLimitAppliesOnRender(t *testing.T) {
+	setLimits(t, Limits{MaxMessageLength: 20})
+	customErr := NewLazy(ErrorInternalServer, "%s", strings.Repeat("a", 40))
+	if customErr.Truncated() {
+		t.Fatal("the lazy message should not be rendered before it is used")
+	}
+	_ = customErr.Error()
+	if !customErr.Truncated() {
+		t.Fatal("the rendered lazy message should be reported as truncated")
+	}
+	// 일반 메시지가 지정되면 지연 메시지의 잘림은 무시됨
+	WithMessage("replaced")(customErr)
+	if customErr.Truncated() {
+		t.Fatal("a replaced lazy message should not count as truncated")
+	}
+}
+
+func TestFieldLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		limits    Limits
+		fields    map[string]any
+		want      map[string]any
+		truncated bool
+	}{
+		{"within limits", Limits{MaxFields: 3, MaxFieldLength: 20}, map[string]any{"a": "x", "b": 1}, map[string]any{"a": "x", "b": 1}, false},
+		{"too many fields", Limits{MaxFields: 2}, map[string]any{"c": 3, "a": 1, "b": 2}, map[string]any{"a": 1, "b": 2}, true},
+		{"long string", Limits{MaxFieldLength: 16}, map[string]any{"a": strings.Repeat("x", 20), "b": 2}, map[string]any{"a": "xx" + TruncatedMarker, "b": 2}, true},
+		{"long bytes", Limits{MaxFieldLength: 16}, map[string]any{"a": []byte(strings.Repeat("x", 20))}, map[string]any{"a": "xx" + TruncatedMarker}, true},
+		{"limit below the marker", Limits{MaxFieldLength: 4}, map[string]any{"a": "xxxxxx"}, map[string]any{"a": TruncatedMarker[:4]}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setLimits(t, tt.limits)
+			customErr := Wrap(io.EOF, WithFields(tt.fields)).(*CustomError)
+			if got := customErr.Fields(); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("Fields = %v, want %v", got, tt.want)
+			}
+			if customErr.Truncated() != tt.truncated {
+				t.Fatalf("Truncated = %v, want %v", customErr.Truncated(), tt.truncated)
+			}
+		})
+	}
+}
+
+func TestSerializedSizeLimit(t *testing.T) {
+	var chain error = io.EOF
+	for i := range 5 {
+		chain = fmt.Errorf("layer %d: %w", i, chain)
+	}
+	customErr := Wrap(chain, WithMessage(strings.Repeat("m", 200)), WithFields(map[string]any{"user": strings.Repeat("u", 100)}),
+		WithHint("hint"), WithPublicMessage("public"))
+	// 각 단계에서 제거한 뒤의 크기를 한도로 사용
+	sizes := make([]int, 0, 3)
+	w := toVersionedWire(customErr, maxEncodeDepth)
+	w.Truncated = true
+	for _, reduce := range []func(){func() { w.Cause = nil }, func() { w.Fields = nil }, func() { w.Traces = nil }} {
+		reduce()
+		data, _ := json.Marshal(w)
+		sizes = append(sizes, len(data))
+	}
+	tests := []struct {
+		name   string
+		limit  int
+		causes bool
+		fields bool
+		traces bool
+	}{
+		{"unlimited", 0, true, true, true},
+		{"chain dropped", sizes[0], false, true, true},
+		{"fields dropped", sizes[1], false, false, true},
+		{"traces dropped", sizes[2], false, false, false},
+		{"message cut", sizes[2] - 50, false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setLimits(t, Limits{MaxSerializedSize: tt.limit})
+			data, err := Encode(customErr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.limit > 0 && len(data) > tt.limit {
+				t.Fatalf("encoded %d bytes, want at most %d: %s", len(data), tt.limit, data)
+			}
+			got, err := Decode(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (got.Unwrap() != nil) != tt.causes || (got.Fields() != nil) != tt.fields || (got.Trace != "") != tt.traces {
+				t.Fatalf("decoded causes %v, fields %v, traces %v: %s", got.Unwrap() != nil, got.Fields() != nil, got.Trace != "", data)
+			}
+			if got.Truncated() != (tt.limit > 0) {
+				t.Fatalf("Truncated = %v: %s", got.Truncated(), data)
+			}
+		})
+	}
+}
+
+func TestTruncatedRoundTrip(t *testing.T) {
+	setLimits(t, Limits{MaxMessageLength: 20})
+	tests := []struct {
+		name      string
+		err       *CustomError
+		truncated bool
+	}{
+		{"not truncated", New("boom", ErrorInternalServer), false},
+		{"truncated message", New(strings.Repeat("a", 40), ErrorInternalServer), true},
+		{"truncated lazy message", NewLazy(ErrorInternalServer, "%s", strings.Repeat("a", 40)), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Encode(tt.err)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Decode(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Truncated() != tt.truncated || got.Error() != tt.err.Error() {
+				t.Fatalf("decoded %q truncated %v, want %q truncated %v", got.Error(), got.Truncated(), tt.err.Error(), tt.truncated)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package exception
+
+import (
+	"errors"
+	"strings"
+)
+
+// WithOp records op, such as "userService.Get", as the logical operation the
+// error passed through. Each wrap may add one, building a path that is easier to
+// read in alerts than the file:line trace; see Ops.
+func WithOp(op string) CustomErrorOption {
+	return func(e *CustomError) { e.ops = append(e.ops, op) }
+}
+
+// WithCallerOp records the name of the function calling Wrap, or another wrap
+// function, as the operation, shortening e.g. "example.com/user.(*Service).Get"
+// to "user.Service.Get"; see WithOp.
+func WithCallerOp() CustomErrorOption {
+	return func(e *CustomError) { e.callerOp = true }
+}
+
+// Ops returns the operations recorded with WithOp across err's chain, outermost
+// first.
+func Ops(err error) []string {
+	var ops []string
+	for ; !isNil(err); err = errors.Unwrap(err) {
+		if e, ok := err.(*CustomError); ok {
+			for i := len(e.ops) - 1; i >= 0; i-- {
+				ops = append(ops, e.ops[i])
+			}
+		}
+	}
+	return ops
+}
+
+// opName shortens a function name to its package and receiver, dropping the
+// import path and pointer markers.
+func opName(function string) string {
+	if i := strings.LastIndexByte(function, '/'); i >= 0 {
+		function = function[i+1:]
+	}
+	return strings.NewReplacer("(*", "", "(", "", ")", "").Replace(function)
+}
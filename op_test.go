@@ -0,0 +1,71 @@
+package exception
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+)
+
+// loadUser wraps err recording its own name as the operation.
+func loadUser(err error) error {
+	return Wrap(err, WithCallerOp())
+}
+
+func TestOps(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{"nil", nil, nil},
+		{"plain error", io.EOF, nil},
+		{"no ops", Wrap(io.EOF), nil},
+		{"one op", Wrap(io.EOF, WithOp("userRepo.Find")), []string{"userRepo.Find"}},
+		{"rewrapped", Wrap(Wrap(io.EOF, WithOp("userRepo.Find")), WithOp("userService.Get")),
+			[]string{"userService.Get", "userRepo.Find"}},
+		{"across a fmt layer", Wrap(fmt.Errorf("query: %w", Wrap(io.EOF, WithOp("userRepo.Find"))), WithOp("userService.Get")),
+			[]string{"userService.Get", "userRepo.Find"}},
+		{"caller op", loadUser(io.EOF), []string{"exception.loadUser"}},
+		{"caller op without trace", Wrap(io.EOF, WithCallerOp(), WithNoTrace()), []string{"exception.TestOps"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Ops(tt.err); !slices.Equal(got, tt.want) {
+				t.Fatalf("Ops = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithCallerOpAppliesToOneWrap(t *testing.T) {
+	err := Wrap(io.EOF, WithCallerOp(), WithNoTrace())
+	if Trace(err) != "" {
+		t.Fatal("WithNoTrace should still skip the trace")
+	}
+	Wrap(err)
+	if got := Ops(err); len(got) != 1 {
+		t.Fatalf("Ops = %q, want the caller op once", got)
+	}
+	if got := Ops(New("boom", ErrorInternalServer, WithCallerOp())); got != nil {
+		t.Fatalf("New does not capture a caller, Ops = %q", got)
+	}
+}
+
+func TestOpName(t *testing.T) {
+	tests := []struct {
+		function string
+		want     string
+	}{
+		{"example.com/user.(*Service).Get", "user.Service.Get"},
+		{"example.com/user.Service.Get", "user.Service.Get"},
+		{"example.com/user.(Service).Get", "user.Service.Get"},
+		{"main.run", "main.run"},
+		{"example.com/user.Get.func1", "user.Get.func1"},
+	}
+	for _, tt := range tests {
+		if got := opName(tt.function); got != tt.want {
+			t.Errorf("opName(%q) = %q, want %q", tt.function, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package exception
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sink receives batches of reported errors, e.g. to forward them to an APM.
+// Implementations must be safe for concurrent use.
+type Sink interface {
+	Send(ctx context.Context, errs []*CustomError) error
+}
+
+// SinkFunc adapts a function to a Sink.
+type SinkFunc func(ctx context.Context, errs []*CustomError) error
+
+func (f SinkFunc) Send(ctx context.Context, errs []*CustomError) error {
+	return f(ctx, errs)
+}
+
+// Reporter reports errors to sinks.
+type Reporter interface {
+	Report(ctx context.Context, err error)
+}
+
+var reporter atomic.Pointer[Reporter]
+
+// SetReporter sets the reporter used by Report; nil disables reporting.
+func SetReporter(r Reporter) {
+	if r == nil {
+		reporter.Store(nil)
+		return
+	}
+	reporter.Store(&r)
+}
+
+// Report reports err with the reporter set by SetReporter. Errors that are not
+// CustomErrors are reported as internal server errors caused by them.
+func Report(ctx context.Context, err error) {
+	if r := reporter.Load(); r != nil && !isNil(err) {
+		(*r).Report(ctx, err)
+	}
+}
+
+// reportable returns the CustomError to report for err.
+func reportable(err error) *CustomError {
+	var customErr *CustomError
+	if errors.As(err, &customErr) && customErr != nil {
+		return customErr
+	}
+	return New(err.Error(), ErrorInternalServer, WithCause(err))
+}
+
+// SyncReporter sends every report to its sinks immediately.
+type SyncReporter struct {
+	sinks   []Sink
+	onError func(error)
+}
+
+// NewSyncReporter creates a reporter sending every report to sinks as it happens.
+// onError, if not nil, receives errors returned by sinks.
+func NewSyncReporter(onError func(error), sinks ...Sink) *SyncReporter {
+	return &SyncReporter{sinks: sinks, onError: onError}
+}
+
+func (r *SyncReporter) Report(ctx context.Context, err error) {
+	if isNil(err) {
+		return
+	}
+	send(ctx, r.sinks, []*CustomError{reportable(err)}, r.onError)
+}
+
+func send(ctx context.Context, sinks []Sink, errs []*CustomError, onError func(error)) {
+	for _, sink := range sinks {
+		if err := sink.Send(ctx, errs); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// BatchOptions configures a BatchReporter.
+type BatchOptions struct {
+	// Interval is how often buffered reports are sent. The default is one second.
+	Interval time.Duration
+	// Size is the number of buffered reports that triggers an early send. The default is 100.
+	Size int
+	// Buffer is the number of reports queued before new ones are dropped. The default is 10 times Size.
+	Buffer int
+	// OnError, if not nil, receives errors returned by sinks.
+	OnError func(error)
+}
+
+// BatchReporter buffers reports in memory and sends them to its sinks in batches,
+// on an interval or when enough reports are buffered, keeping sinks off the
+// request path. Reports are dropped when the buffer is full.
+type BatchReporter struct {
+	sinks   []Sink
+	opts    BatchOptions
+	queue   chan *CustomError
+	flushes chan flushRequest
+	done    chan struct{}
+	stopped chan struct{}
+	closed  atomic.Bool
+	once    sync.Once
+	dropped atomic.Int64
+}
+
+type flushRequest struct {
+	ctx  context.Context
+	done chan struct{}
+}
+
+// NewBatchReporter creates a BatchReporter sending to sinks and starts its
+// background loop. Call Close on shutdown to send the remaining reports.
+func NewBatchReporter(opts BatchOptions, sinks ...Sink) *BatchReporter {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.Size <= 0 {
+		opts.Size = 100
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = 10 * opts.Size
+	}
+	r := &BatchReporter{
+		sinks:   sinks,
+		opts:    opts,
+		queue:   make(chan *CustomError, opts.Buffer),
+		flushes: make(chan flushRequest),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Report queues a copy of err without blocking, so the caller may keep wrapping it
+// while it waits to be sent. It is dropped if the buffer is full or the reporter
+// is closed.
+func (r *BatchReporter) Report(ctx context.Context, err error) {
+	if isNil(err) || r.closed.Load() {
+		return
+	}
+	select {
+	case r.queue <- reportable(err).clone():
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of reports dropped because the buffer was full.
+func (r *BatchReporter) Dropped() int64 {
+	return r.dropped.Load()
+}
+
+// Flush sends all buffered reports and waits until the sinks return or ctx is done.
+func (r *BatchReporter) Flush(ctx context.Context) error {
+	req := flushRequest{ctx: ctx, done: make(chan struct{})}
+	select {
+	case r.flushes <- req:
+	case <-r.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-req.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting reports, sends the buffered ones and stops the background
+// loop, waiting until it finishes or ctx is done.
+func (r *BatchReporter) Close(ctx context.Context) error {
+	r.once.Do(func() {
+		r.closed.Store(true)
+		close(r.done)
+	})
+	select {
+	case <-r.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *BatchReporter) run() {
+	defer close(r.stopped)
+	ticker := time.NewTicker(r.opts.Interval)
+	defer ticker.Stop()
+	batch := make([]*CustomError, 0, r.opts.Size)
+	sendBatch := func(ctx context.Context) {
+		if len(batch) == 0 {
+			return
+		}
+		send(ctx, r.sinks, batch, r.opts.OnError)
+		batch = make([]*CustomError, 0, r.opts.Size)
+	}
+	drain := func() {
+		for {
+			select {
+			case e := <-r.queue:
+				batch = append(batch, e)
+			default:
+				return
+			}
+		}
+	}
+	for {
+		select {
+		case e := <-r.queue:
+			batch = append(batch, e)
+			if len(batch) >= r.opts.Size {
+				sendBatch(context.Background())
+			}
+		case <-ticker.C:
+			sendBatch(context.Background())
+		case req := <-r.flushes:
+			drain()
+			sendBatch(req.ctx)
+			close(req.done)
+		case <-r.done:
+			drain()
+			sendBatch(context.Background())
+			return
+		}
+	}
+}
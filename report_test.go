@@ -0,0 +1,215 @@
+package exception
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSink records every error it receives.
+type recordingSink struct {
+	mu      sync.Mutex
+	batches [][]*CustomError
+}
+
+func (s *recordingSink) Send(ctx context.Context, errs []*CustomError) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, errs)
+	return nil
+}
+
+func (s *recordingSink) errs() []*CustomError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var all []*CustomError
+	for _, batch := range s.batches {
+		all = append(all, batch...)
+	}
+	return all
+}
+
+func TestReportable(t *testing.T) {
+	customErr := New("user missing", ErrorNotFound)
+	tests := []struct {
+		name string
+		err  error
+		want func(*CustomError) bool
+	}{
+		{"custom error", customErr, func(got *CustomError) bool { return got == customErr }},
+		{"wrapped custom error", fmt.Errorf("load: %w", customErr), func(got *CustomError) bool { return got == customErr }},
+		{"plain error", io.EOF, func(got *CustomError) bool {
+			return got.Code() == ErrorInternalServer && got.Error() == "EOF" && errors.Is(got, io.EOF)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reportable(tt.err); !tt.want(got) {
+				t.Fatalf("reportable = %v (%d)", got, got.Code())
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	var nilErr *CustomError
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"error", io.EOF, 1},
+		{"nil", nil, 0},
+		{"typed nil", nilErr, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordingSink{}
+			SetReporter(NewSyncReporter(nil, sink))
+			t.Cleanup(func() { SetReporter(nil) })
+			Report(context.Background(), tt.err)
+			if got := len(sink.errs()); got != tt.want {
+				t.Fatalf("reported %d errors, want %d", got, tt.want)
+			}
+			SetReporter(nil)
+			Report(context.Background(), io.EOF)
+			if got := len(sink.errs()); got != tt.want {
+				t.Fatal("Report should do nothing without a reporter")
+			}
+		})
+	}
+}
+
+func TestSyncReporter(t *testing.T) {
+	sink := &recordingSink{}
+	var sinkErrs []error
+	failing := SinkFunc(func(ctx context.Context, errs []*CustomError) error { return io.ErrClosedPipe })
+	r := NewSyncReporter(func(err error) { sinkErrs = append(sinkErrs, err) }, sink, failing)
+
+	r.Report(context.Background(), io.EOF)
+	r.Report(context.Background(), nil)
+	got := sink.errs()
+	if len(got) != 1 || got[0].Code() != ErrorInternalServer || !errors.Is(got[0], io.EOF) {
+		t.Fatalf("reported = %v", got)
+	}
+	if len(sinkErrs) != 1 || sinkErrs[0] != io.ErrClosedPipe {
+		t.Fatalf("sink errors = %v", sinkErrs)
+	}
+}
+
+func TestBatchReporterSendsFullBatch(t *testing.T) {
+	sink := &recordingSink{}
+	r := NewBatchReporter(BatchOptions{Interval: time.Hour, Size: 2}, sink)
+	defer r.Close(context.Background())
+	for range 2 {
+		r.Report(context.Background(), New("boom", ErrorInternalServer))
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(sink.errs()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("a full batch was not sent before the interval")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBatchReporterSendsOnClose(t *testing.T) {
+	sink := &recordingSink{}
+	r := NewBatchReporter(BatchOptions{Interval: time.Hour, Size: 2}, sink)
+	for range 3 {
+		r.Report(context.Background(), New("boom", ErrorInternalServer))
+	}
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(sink.errs()); got != 3 {
+		t.Fatalf("sent %d errors, want 3", got)
+	}
+	r.Report(context.Background(), New("late", ErrorInternalServer))
+	if got := len(sink.errs()); got != 3 {
+		t.Fatal("a closed reporter should drop reports")
+	}
+}
+
+func TestBatchReporterFlush(t *testing.T) {
+	sink := &recordingSink{}
+	r := NewBatchReporter(BatchOptions{Interval: time.Hour, Size: 100}, sink)
+	defer r.Close(context.Background())
+	r.Report(context.Background(), New("boom", ErrorInternalServer))
+	if err := r.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(sink.errs()); got != 1 {
+		t.Fatalf("flushed %d errors, want 1", got)
+	}
+}
+
+func TestBatchReporterReportsSnapshot(t *testing.T) {
+	sink := &recordingSink{}
+	reading := SinkFunc(func(ctx context.Context, errs []*CustomError) error {
+		for _, e := range errs {
+			_ = e.Error() + e.PrintTrace()
+		}
+		return nil
+	})
+	r := NewBatchReporter(BatchOptions{Interval: time.Millisecond, Size: 100}, reading, sink)
+	defer r.Close(context.Background())
+	err := New("boom", ErrorInternalServer)
+	r.Report(context.Background(), err)
+	// 보고 후 계속 감싸도 전송 중인 에러와 경합하지 않음 (go test -race)
+	for range 100 {
+		_ = WrapMessage(err, "retrying")
+	}
+	if err := r.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	errs := sink.errs()
+	if len(errs) != 1 || errs[0] == err || errs[0].Error() != "boom" || errs[0].PreviousTraces != nil {
+		t.Fatalf("sent %+v", errs)
+	}
+}
+
+func TestBatchReporterDropsWhenFull(t *testing.T) {
+	release := make(chan struct{})
+	blocking := SinkFunc(func(ctx context.Context, errs []*CustomError) error {
+		<-release
+		return nil
+	})
+	r := NewBatchReporter(BatchOptions{Interval: time.Hour, Size: 1, Buffer: 1}, blocking)
+	defer r.Close(context.Background())
+	defer close(release)
+	// 첫 보고는 싱크에서 막히고, 두 번째는 버퍼를 채우고, 나머지는 버려짐
+	deadline := time.Now().Add(5 * time.Second)
+	for r.Dropped() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("no report was dropped with a full buffer")
+		}
+		r.Report(context.Background(), New("boom", ErrorInternalServer))
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBatchReporterDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		opts BatchOptions
+		want BatchOptions
+	}{
+		{"zero", BatchOptions{}, BatchOptions{Interval: time.Second, Size: 100, Buffer: 1000}},
+		{"size only", BatchOptions{Size: 10}, BatchOptions{Interval: time.Second, Size: 10, Buffer: 100}},
+		{"explicit", BatchOptions{Interval: time.Minute, Size: 5, Buffer: 7}, BatchOptions{Interval: time.Minute, Size: 5, Buffer: 7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewBatchReporter(tt.opts)
+			defer r.Close(context.Background())
+			if r.opts.Interval != tt.want.Interval || r.opts.Size != tt.want.Size || r.opts.Buffer != tt.want.Buffer || cap(r.queue) != tt.want.Buffer {
+				t.Fatalf("opts = %+v, queue %d", r.opts, cap(r.queue))
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package exception
+
+import (
+	"errors"
+	"time"
+)
+
+// RetryPolicy derives the retry behavior from the first CustomError in err's chain.
+// An explicit WithRetryable flag takes precedence; otherwise errors with a
+// Retry-After delay or a transient code (timeouts, rate limiting, unavailable
+// upstreams) are retried. after is the delay requested by the error, or 0 to let
+// the caller's backoff decide.
+func RetryPolicy(err error) (shouldRetry bool, after time.Duration) {
+	var customErr *CustomError
+	if !errors.As(err, &customErr) || customErr == nil {
+		return false, 0
+	}
+	if customErr.retryable != nil {
+		shouldRetry = *customErr.retryable
+	} else {
+		shouldRetry = customErr.retryAfter > 0 || isRetryableCode(customErr.code)
+	}
+	if !shouldRetry {
+		return false, 0
+	}
+	return true, customErr.retryAfter
+}
+
+// IsRetryable reports whether the first CustomError in err's chain may succeed when
+// retried; see RetryPolicy.
+func IsRetryable(err error) bool {
+	shouldRetry, _ := RetryPolicy(err)
+	return shouldRetry
+}
@@ -0,0 +1,59 @@
+package exception
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		retry bool
+		after time.Duration
+	}{
+		{"plain error", errors.New("boom"), false, 0},
+		{"transient code", New("upstream down", ErrorCode(503)), true, 0},
+		{"retry after", New("slow down", ErrorCode(429), WithRetryAfter(time.Second)), true, time.Second},
+		{"retry after makes retryable", New("db down", ErrorInternalServer, WithRetryAfter(time.Second)), true, time.Second},
+		{"explicit flag wins over retry after", New("slow down", ErrorCode(429), WithRetryAfter(time.Second), WithRetryable(false)), false, 0},
+		{"client error", New("bad input", ErrorDataInvalid), false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retry, after := RetryPolicy(tt.err)
+			if retry != tt.retry || after != tt.after {
+				t.Fatalf("RetryPolicy = %v, %s, want %v, %s", retry, after, tt.retry, tt.after)
+			}
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("boom"), false},
+		{"client error", New("bad input", ErrorDataInvalid), false},
+		{"server error", New("db down", ErrorInternalServer), false},
+		{"unavailable", New("upstream down", ErrorCode(503)), true},
+		{"too many requests", New("slow down", ErrorCode(429)), true},
+		{"timeout", New("timeout", ErrorCode(504)), true},
+		{"explicitly retryable", New("db down", ErrorInternalServer, WithRetryable(true)), true},
+		{"explicitly not retryable", New("upstream down", ErrorCode(503), WithRetryable(false)), false},
+		{"retry after", New("db down", ErrorInternalServer, WithRetryAfter(time.Second)), true},
+		{"wrapped", fmt.Errorf("call: %w", New("upstream down", ErrorCode(503))), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Fatalf("IsRetryable = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package exception
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FieldSuppressed holds, on a sampled report, how many errors with the same
+// fingerprint were dropped since the previous report; see SampleSink.
+const FieldSuppressed = "sampling.suppressed"
+
+// SampleOptions configures SampleSink. Each limit applies per fingerprint; an
+// error is sent only when it passes all of the limits set.
+type SampleOptions struct {
+	// EveryN sends the first error and then one in every N.
+	EveryN int
+	// PerMinute sends at most this many errors per minute, allowing bursts of up
+	// to the same number.
+	PerMinute int
+	// MaxFingerprints bounds the fingerprints tracked; when exceeded, the state
+	// of all of them is reset. The default is 10000.
+	MaxFingerprints int
+}
+
+type sampleState struct {
+	seen       int64
+	suppressed int64
+	tokens     float64
+	refilled   time.Time
+}
+
+// SampleSink returns a Sink passing to sink only a sample of the errors sharing a
+// fingerprint (see Fingerprint), so noisy errors do not exhaust the quota of an
+// APM. The first error sent after some were dropped is a copy carrying the number
+// dropped under FieldSuppressed.
+func SampleSink(sink Sink, opts SampleOptions) Sink {
+	s := newSampler(opts)
+	return SinkFunc(func(ctx context.Context, errs []*CustomError) error {
+		now := time.Now()
+		kept := make([]*CustomError, 0, len(errs))
+		for _, e := range errs {
+			keep, suppressed := s.sample(e.Fingerprint(), now)
+			if !keep {
+				continue
+			}
+			if suppressed > 0 {
+				e = e.WithFieldC(FieldSuppressed, suppressed)
+			}
+			kept = append(kept, e)
+		}
+		if len(kept) == 0 {
+			return nil
+		}
+		return sink.Send(ctx, kept)
+	})
+}
+
+// sampler tracks the sampling state of each fingerprint.
+type sampler struct {
+	opts   SampleOptions
+	mu     sync.Mutex
+	states map[string]*sampleState
+}
+
+func newSampler(opts SampleOptions) *sampler {
+	if opts.MaxFingerprints <= 0 {
+		opts.MaxFingerprints = 10000
+	}
+	return &sampler{opts: opts, states: make(map[string]*sampleState)}
+}
+
+// sample reports whether an error with fingerprint seen at now is sent, and how
+// many were dropped before it.
+func (s *sampler) sample(fingerprint string, now time.Time) (bool, int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[fingerprint]
+	if !ok {
+		if len(s.states) >= s.opts.MaxFingerprints {
+			clear(s.states)
+		}
+		state = &sampleState{tokens: float64(s.opts.PerMinute), refilled: now}
+		s.states[fingerprint] = state
+	}
+	state.seen++
+	keep := s.opts.EveryN <= 1 || (state.seen-1)%int64(s.opts.EveryN) == 0
+	if s.opts.PerMinute > 0 {
+		// 경과 시간만큼 토큰을 채운 뒤 하나를 소비
+		burst := float64(s.opts.PerMinute)
+		state.tokens = min(burst, state.tokens+now.Sub(state.refilled).Minutes()*burst)
+		state.refilled = now
+		if keep && state.tokens >= 1 {
+			state.tokens--
+		} else {
+			keep = false
+		}
+	}
+	if !keep {
+		state.suppressed++
+		return false, 0
+	}
+	suppressed := state.suppressed
+	state.suppressed = 0
+	return true, suppressed
+}
@@ -0,0 +1,104 @@
+package exception
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// sampleCall is an error with fingerprint seen at offset after the start, and
+// the expected sampling decision.
+type sampleCall struct {
+	fingerprint string
+	offset      time.Duration
+	keep        bool
+	suppressed  int64
+}
+
+func TestSampler(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  SampleOptions
+		calls []sampleCall
+	}{
+		{"unlimited", SampleOptions{}, []sampleCall{
+			{"a", 0, true, 0}, {"a", 0, true, 0},
+		}},
+		{"every n", SampleOptions{EveryN: 3}, []sampleCall{
+			{"a", 0, true, 0}, {"a", 0, false, 0}, {"a", 0, false, 0}, {"a", 0, true, 2}, {"a", 0, false, 0},
+		}},
+		{"per fingerprint", SampleOptions{EveryN: 2}, []sampleCall{
+			{"a", 0, true, 0}, {"b", 0, true, 0}, {"a", 0, false, 0}, {"b", 0, false, 0}, {"a", 0, true, 1},
+		}},
+		{"per minute", SampleOptions{PerMinute: 2}, []sampleCall{
+			{"a", 0, true, 0}, {"a", time.Second, true, 0}, {"a", 2 * time.Second, false, 0},
+			// 30초 뒤에는 토큰 하나가 다시 채워짐
+			{"a", 32 * time.Second, true, 1}, {"a", 33 * time.Second, false, 0},
+		}},
+		{"both limits", SampleOptions{EveryN: 2, PerMinute: 1}, []sampleCall{
+			{"a", 0, true, 0}, {"a", 0, false, 0}, {"a", 0, false, 0}, {"a", time.Minute, false, 0}, {"a", time.Minute, true, 3},
+		}},
+		{"max fingerprints resets", SampleOptions{EveryN: 2, MaxFingerprints: 1}, []sampleCall{
+			{"a", 0, true, 0}, {"a", 0, false, 0}, {"b", 0, true, 0}, {"a", 0, true, 0},
+		}},
+	}
+	start := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSampler(tt.opts)
+			for i, call := range tt.calls {
+				keep, suppressed := s.sample(call.fingerprint, start.Add(call.offset))
+				if keep != call.keep || suppressed != call.suppressed {
+					t.Fatalf("call %d: sample = %v, %d, want %v, %d", i, keep, suppressed, call.keep, call.suppressed)
+				}
+			}
+		})
+	}
+}
+
+func TestSampleSink(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       SampleOptions
+		n          int
+		kept       int
+		suppressed []any
+	}{
+		{"every other", SampleOptions{EveryN: 2}, 5, 3, []any{nil, int64(1), int64(1)}},
+		{"first only", SampleOptions{EveryN: 10}, 3, 1, []any{nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := make([]*CustomError, tt.n)
+			for i := range errs {
+				errs[i] = New("boom", ErrorInternalServer)
+			}
+			sink := &recordingSink{}
+			if err := SampleSink(sink, tt.opts).Send(context.Background(), errs); err != nil {
+				t.Fatal(err)
+			}
+			got := sink.errs()
+			if len(got) != tt.kept || len(sink.batches) != 1 {
+				t.Fatalf("sent %d errors in %d batches, want %d in 1", len(got), len(sink.batches), tt.kept)
+			}
+			for i, e := range got {
+				if v, _ := e.Field(FieldSuppressed); v != tt.suppressed[i] {
+					t.Fatalf("error %d: %s = %v, want %v", i, FieldSuppressed, v, tt.suppressed[i])
+				}
+			}
+			// 억제 수는 복사본에만 기록
+			for _, e := range errs {
+				if _, ok := e.Field(FieldSuppressed); ok {
+					t.Fatal("SampleSink modified a reported error")
+				}
+			}
+		})
+	}
+	sink := &recordingSink{}
+	sampled := SampleSink(sink, SampleOptions{EveryN: 2})
+	err := New("boom", ErrorInternalServer)
+	sampled.Send(context.Background(), []*CustomError{err})
+	if sampled.Send(context.Background(), []*CustomError{err}) != nil || len(sink.batches) != 1 {
+		t.Fatal("a batch with no sampled errors should not be sent")
+	}
+}
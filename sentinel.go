@@ -0,0 +1,41 @@
+package exception
+
+import "sync"
+
+var (
+	sentinelMu sync.RWMutex
+	sentinels  = make(map[ErrorCode]*CustomError)
+)
+
+// RegisterSentinel registers err as the well-known error for code and returns the
+// registered instance. The sentinel is frozen: wrapping it or calling its With*
+// methods works on a copy, so it can be shared safely. Registering the same code
+// again replaces the previous sentinel.
+func RegisterSentinel(code ErrorCode, err *CustomError) *CustomError {
+	if err == nil {
+		return nil
+	}
+	sentinel := err.clone()
+	sentinel.code = code
+	sentinel.frozen = true
+
+	sentinelMu.Lock()
+	defer sentinelMu.Unlock()
+	sentinels[code] = sentinel
+	return sentinel
+}
+
+// SentinelFor returns the sentinel registered for code.
+func SentinelFor(code ErrorCode) (*CustomError, bool) {
+	sentinelMu.RLock()
+	defer sentinelMu.RUnlock()
+	sentinel, ok := sentinels[code]
+	return sentinel, ok
+}
+
+// Shared reports whether e is an instance shared by many occurrences, a
+// registered sentinel or an interned error, so its ID does not identify a single
+// occurrence.
+func (e *CustomError) Shared() bool {
+	return e != nil && e.frozen
+}
@@ -0,0 +1,98 @@
+package exception
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func resetSentinels(t *testing.T) {
+	t.Helper()
+	sentinelMu.Lock()
+	saved := sentinels
+	sentinels = make(map[ErrorCode]*CustomError)
+	sentinelMu.Unlock()
+	t.Cleanup(func() {
+		sentinelMu.Lock()
+		sentinels = saved
+		sentinelMu.Unlock()
+	})
+}
+
+func TestRegisterSentinel(t *testing.T) {
+	resetSentinels(t)
+	src := New("user missing", ErrorInternalServer)
+	sentinel := RegisterSentinel(ErrorUserNotFound, src)
+	if sentinel == src || sentinel.Code() != ErrorUserNotFound || src.Code() != ErrorInternalServer {
+		t.Fatalf("RegisterSentinel should register a copy with the code: %+v", sentinel)
+	}
+	got, ok := SentinelFor(ErrorUserNotFound)
+	if !ok || got != sentinel {
+		t.Fatalf("SentinelFor = %v, %v", got, ok)
+	}
+	if _, ok := SentinelFor(ErrorUserExists); ok {
+		t.Fatal("SentinelFor should miss an unregistered code")
+	}
+
+	replaced := RegisterSentinel(ErrorUserNotFound, New("no such user", 0))
+	if got, _ := SentinelFor(ErrorUserNotFound); got != replaced {
+		t.Fatal("registering a code again should replace the sentinel")
+	}
+	if RegisterSentinel(ErrorUserNotFound, nil) != nil {
+		t.Fatal("RegisterSentinel(nil) should return nil")
+	}
+}
+
+func TestSentinelIsNotMutated(t *testing.T) {
+	resetSentinels(t)
+	sentinel := RegisterSentinel(ErrorUserNotFound, New("user missing", 0))
+
+	tests := []struct {
+		name string
+		use  func() error
+	}{
+		{"Wrap", func() error { return Wrap(sentinel, WithMessage("load user"), WithField("id", 7)) }},
+		{"WrapMessageWithCode", func() error { return WrapMessageWithCode(sentinel, ErrorUserExists, "load user") }},
+		{"WithCodeC", func() error { return sentinel.WithCodeC(ErrorUserExists) }},
+		{"WithFieldC", func() error { return sentinel.WithFieldC("id", 7) }},
+		{"WithPublic", func() error { return sentinel.WithPublic("not found") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.use()
+			var customErr *CustomError
+			if !errors.As(err, &customErr) || customErr == sentinel || customErr.frozen {
+				t.Fatalf("%s should work on an unfrozen copy: %#v", tt.name, err)
+			}
+		})
+	}
+	if sentinel.Message != "user missing" || sentinel.Code() != ErrorUserNotFound || sentinel.Trace != "" ||
+		sentinel.Fields() != nil || sentinel.PublicMessage() != "" || !sentinel.frozen {
+		t.Fatalf("sentinel changed: %+v", sentinel)
+	}
+	if WrapMessage(io.EOF, "x").(*CustomError).frozen {
+		t.Fatal("ordinary errors should not be frozen")
+	}
+}
+
+func TestShared(t *testing.T) {
+	resetSentinels(t)
+	sentinel := RegisterSentinel(ErrorUserNotFound, New("user missing", 0))
+	var nilErr *CustomError
+	tests := []struct {
+		name string
+		err  *CustomError
+		want bool
+	}{
+		{"sentinel", sentinel, true},
+		{"interned", NewInterner(1).New("rejected", ErrorBadRequest), true},
+		{"wrapped sentinel", Wrap(sentinel).(*CustomError), false},
+		{"new error", New("boom", ErrorInternalServer), false},
+		{"nil", nilErr, false},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Shared(); got != tt.want {
+			t.Errorf("%s: Shared = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package exception
+
+import "errors"
+
+// Severity ranks how serious an error is, for log levels and alerting.
+type Severity int
+
+const (
+	SeverityDebug Severity = iota + 1
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityDebug:
+		return "debug"
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	}
+	return "unknown"
+}
+
+// wireSeverity returns the name of a severity set with WithSeverity, and "" for a
+// severity derived from the code, which the receiver derives again.
+func wireSeverity(s Severity) string {
+	if s == 0 {
+		return ""
+	}
+	return s.String()
+}
+
+// parseSeverity returns the severity named by s, as written by Severity.String.
+func parseSeverity(s string) (Severity, bool) {
+	for severity := SeverityDebug; severity <= SeverityCritical; severity++ {
+		if severity.String() == s {
+			return severity, true
+		}
+	}
+	return 0, false
+}
+
+// Severity returns the severity set with WithSeverity, or otherwise one derived
+// from the code: info for requests the client closed, warnings for other client
+// errors (4xx) and errors for everything else.
+func (e *CustomError) Severity() Severity {
+	if e == nil {
+		return 0
+	}
+	if e.severity != 0 {
+		return e.severity
+	}
+	if e.code == ErrorClientClosedRequest {
+		return SeverityInfo
+	}
+	if e.code >= 400 && e.code < 500 {
+		return SeverityWarning
+	}
+	return SeverityError
+}
+
+// SeverityOf returns the severity of the first CustomError in err's chain, and
+// SeverityError for other non-nil errors.
+func SeverityOf(err error) Severity {
+	var customErr *CustomError
+	if errors.As(err, &customErr) && customErr != nil {
+		return customErr.Severity()
+	}
+	if isNil(err) {
+		return 0
+	}
+	return SeverityError
+}
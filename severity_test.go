@@ -0,0 +1,85 @@
+package exception
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestSeverityOf(t *testing.T) {
+	var nilErr *CustomError
+	tests := []struct {
+		name string
+		err  error
+		want Severity
+	}{
+		{"nil", nil, 0},
+		{"typed nil", nilErr, 0},
+		{"plain error", io.EOF, SeverityError},
+		{"client error", New("missing", ErrorNotFound), SeverityWarning},
+		{"server error", New("boom", ErrorInternalServer), SeverityError},
+		{"client closed", New("gone", ErrorClientClosedRequest), SeverityInfo},
+		{"override", New("missing", ErrorNotFound, WithSeverity(SeverityDebug)), SeverityDebug},
+		{"fmt wrapped", fmt.Errorf("load: %w", New("boom", ErrorInternalServer, WithSeverity(SeverityCritical))), SeverityCritical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SeverityOf(tt.err); got != tt.want {
+				t.Fatalf("SeverityOf = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeverityString(t *testing.T) {
+	tests := []struct {
+		severity Severity
+		want     string
+	}{
+		{SeverityDebug, "debug"},
+		{SeverityInfo, "info"},
+		{SeverityWarning, "warning"},
+		{SeverityError, "error"},
+		{SeverityCritical, "critical"},
+		{0, "unknown"},
+	}
+	for _, tt := range tests {
+		if got := tt.severity.String(); got != tt.want {
+			t.Errorf("Severity(%d).String() = %q, want %q", tt.severity, got, tt.want)
+		}
+		got, ok := parseSeverity(tt.want)
+		if ok != (tt.severity != 0) || ok && got != tt.severity {
+			t.Errorf("parseSeverity(%q) = %s, %v", tt.want, got, ok)
+		}
+	}
+}
+
+func TestSeverityRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *CustomError
+		want Severity
+		wire string
+	}{
+		{"derived", New("missing", ErrorNotFound), SeverityWarning, ""},
+		{"override", New("missing", ErrorNotFound, WithSeverity(SeverityCritical)), SeverityCritical, "critical"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toWire(tt.err, 1).Severity; got != tt.wire {
+				t.Fatalf("wire severity = %q, want %q", got, tt.wire)
+			}
+			data, err := Encode(tt.err)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Decode(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Severity() != tt.want {
+				t.Fatalf("decoded severity = %s, want %s: %s", got.Severity(), tt.want, data)
+			}
+		})
+	}
+}
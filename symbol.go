@@ -0,0 +1,94 @@
+package exception
+
+import (
+	"container/list"
+	"runtime"
+	"sync"
+)
+
+// defaultSymbolCacheSize is the number of program counters whose symbolized frames are cached.
+const defaultSymbolCacheSize = 4096
+
+// symbolCache is a bounded LRU cache from a program counter to its frames, so
+// repeated capture sites skip runtime symbolization.
+type symbolCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[uintptr]*list.Element
+	order   *list.List
+}
+
+type symbolEntry struct {
+	pc     uintptr
+	frames []Frame
+	// text is frames formatted as a trace entry.
+	text string
+}
+
+var symbols = newSymbolCache(defaultSymbolCacheSize)
+
+func newSymbolCache(size int) *symbolCache {
+	return &symbolCache{size: size, entries: make(map[uintptr]*list.Element), order: list.New()}
+}
+
+// SetSymbolCacheSize sets how many capture sites keep their symbolized frames
+// cached; 0 disables the cache. It should be called during initialization.
+func SetSymbolCacheSize(size int) {
+	if size < 0 {
+		size = 0
+	}
+	symbols.mu.Lock()
+	defer symbols.mu.Unlock()
+	symbols.size = size
+	for symbols.order.Len() > size {
+		symbols.evictOldest()
+	}
+}
+
+// lookup returns the frames for pc and their formatting, symbolizing pc on a cache
+// miss. A pc covering inlined calls expands to several frames.
+func (c *symbolCache) lookup(pc uintptr) *symbolEntry {
+	c.mu.Lock()
+	if el, ok := c.entries[pc]; ok {
+		c.order.MoveToFront(el)
+		entry := el.Value.(*symbolEntry)
+		c.mu.Unlock()
+		return entry
+	}
+	c.mu.Unlock()
+
+	entry := symbolize(pc)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.size == 0 {
+		return entry
+	}
+	if _, ok := c.entries[pc]; !ok {
+		c.entries[pc] = c.order.PushFront(entry)
+		for c.order.Len() > c.size {
+			c.evictOldest()
+		}
+	}
+	return entry
+}
+
+func (c *symbolCache) evictOldest() {
+	el := c.order.Back()
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*symbolEntry).pc)
+}
+
+func symbolize(pc uintptr) *symbolEntry {
+	entry := &symbolEntry{pc: pc}
+	frames := runtime.CallersFrames([]uintptr{pc})
+	for {
+		frame, more := frames.Next()
+		entry.frames = append(entry.frames, Frame{File: frame.File, Line: frame.Line, Function: frame.Function})
+		if !more {
+			break
+		}
+	}
+	entry.text = formatTrace(entry.frames, 1)
+	return entry
+}
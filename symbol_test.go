@@ -0,0 +1,108 @@
+package exception
+
+import (
+	"io"
+	"runtime"
+	"testing"
+)
+
+func callerPCs() []uintptr {
+	pcs := make([]uintptr, 16)
+	return pcs[:runtime.Callers(1, pcs)]
+}
+
+func TestSymbolCacheMatchesSymbolize(t *testing.T) {
+	c := newSymbolCache(8)
+	for _, pc := range callerPCs() {
+		want := symbolize(pc).text
+		for range 2 {
+			if got := c.lookup(pc).text; got != want {
+				t.Fatalf("lookup(%#x) = %q, want %q", pc, got, want)
+			}
+		}
+	}
+}
+
+func TestSymbolCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	pcs := callerPCs()
+	if len(pcs) < 3 {
+		t.Skip("not enough frames")
+	}
+	c := newSymbolCache(2)
+	c.lookup(pcs[0])
+	c.lookup(pcs[1])
+	c.lookup(pcs[0])
+	c.lookup(pcs[2])
+	if _, ok := c.entries[pcs[1]]; ok {
+		t.Fatal("least recently used entry was kept")
+	}
+	if _, ok := c.entries[pcs[0]]; !ok {
+		t.Fatal("recently used entry was evicted")
+	}
+	if c.order.Len() != 2 {
+		t.Fatalf("cache holds %d entries, want 2", c.order.Len())
+	}
+}
+
+func TestSetSymbolCacheSize(t *testing.T) {
+	pcs := callerPCs()
+	tests := []struct {
+		name string
+		size int
+		want int
+	}{
+		{"disabled", 0, 0},
+		{"negative", -1, 0},
+		{"shrunk", 1, 1},
+		{"large", 64, len(pcs)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetSymbolCacheSize(defaultSymbolCacheSize)
+			t.Cleanup(func() { SetSymbolCacheSize(defaultSymbolCacheSize) })
+			for _, pc := range pcs {
+				symbols.lookup(pc)
+			}
+			SetSymbolCacheSize(tt.size)
+			for _, pc := range pcs {
+				if got := symbols.lookup(pc).text; got != symbolize(pc).text {
+					t.Fatalf("lookup(%#x) = %q", pc, got)
+				}
+			}
+			if n := symbols.order.Len(); n > tt.want || (tt.want == len(pcs) && n < len(pcs)) {
+				t.Fatalf("cache holds %d entries, want %d", n, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkSymbolize(b *testing.B) {
+	pc := callerPCs()[0]
+	b.Run("cached", func(b *testing.B) {
+		c := newSymbolCache(defaultSymbolCacheSize)
+		c.lookup(pc)
+		b.ReportAllocs()
+		for b.Loop() {
+			c.lookup(pc)
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			symbolize(pc)
+		}
+	})
+}
+
+func BenchmarkWrap(b *testing.B) {
+	run := func(b *testing.B, size int) {
+		SetSymbolCacheSize(size)
+		defer SetSymbolCacheSize(defaultSymbolCacheSize)
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = Wrap(io.EOF, WithCode(ErrorInternalServer))
+		}
+	}
+	b.Run("cached", func(b *testing.B) { run(b, defaultSymbolCacheSize) })
+	b.Run("uncached", func(b *testing.B) { run(b, 0) })
+}
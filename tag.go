@@ -0,0 +1,143 @@
+package exception
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Label names returned by MetricLabels.
+const (
+	LabelCode     = "code"
+	LabelDomain   = "domain"
+	LabelSeverity = "severity"
+	LabelTags     = "tags"
+)
+
+// WithTags adds tags categorizing the error, such as "db" or "expected". Unlike
+// fields, tags carry no value; adding a tag twice has no effect.
+func WithTags(tags ...string) CustomErrorOption {
+	return func(e *CustomError) {
+		for _, tag := range tags {
+			e.addTag(tag)
+		}
+	}
+}
+
+func (e *CustomError) addTag(tag string) {
+	if !slices.Contains(e.tags, tag) {
+		e.tags = append(e.tags, tag)
+	}
+}
+
+// Tags returns the tags of the error in the order they were added.
+func (e *CustomError) Tags() []string {
+	if e == nil {
+		return nil
+	}
+	return slices.Clone(e.tags)
+}
+
+// HasTag reports whether any CustomError in err's chain, including errors
+// joined with errors.Join, has tag.
+func HasTag(err error, tag string) bool {
+	found := false
+	walkChain(err, func(e *CustomError) bool {
+		found = slices.Contains(e.tags, tag)
+		return !found
+	})
+	return found
+}
+
+// Tags returns the tags of every CustomError in err's chain, including errors
+// joined with errors.Join, outermost first and without duplicates.
+func Tags(err error) []string {
+	var tags []string
+	walkChain(err, func(e *CustomError) bool {
+		for _, tag := range e.tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		return true
+	})
+	return tags
+}
+
+// walkChain calls fn for each CustomError in err's tree in depth-first order, as
+// errors.Is visits it, until fn returns false.
+func walkChain(err error, fn func(*CustomError) bool) bool {
+	if isNil(err) {
+		return true
+	}
+	if e, ok := err.(*CustomError); ok && !fn(e) {
+		return false
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return walkChain(u.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, child := range u.Unwrap() {
+			if !walkChain(child, fn) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// MetricLabels returns the labels describing err on metrics: its code, domain,
+// severity and tags, the tags sorted and comma-separated. Every label is present,
+// possibly empty, so that a metric keeps the same label set. Errors without a
+// CustomError are labelled as internal server errors. It returns nil when err is
+// nil.
+func MetricLabels(err error) map[string]string {
+	if isNil(err) {
+		return nil
+	}
+	code, domain := ErrorInternalServer, ""
+	var customErr *CustomError
+	if errors.As(err, &customErr) && customErr != nil {
+		code, domain = customErr.code, customErr.domain
+	}
+	tags := Tags(err)
+	slices.Sort(tags)
+	return map[string]string{
+		LabelCode:     strconv.Itoa(int(code)),
+		LabelDomain:   domain,
+		LabelSeverity: SeverityOf(err).String(),
+		LabelTags:     strings.Join(tags, ","),
+	}
+}
+
+// FilterSink returns a Sink passing to sink only the errors keep accepts, e.g.
+// FilterSink(sentry, ExcludeTags("expected")).
+func FilterSink(sink Sink, keep func(*CustomError) bool) Sink {
+	return SinkFunc(func(ctx context.Context, errs []*CustomError) error {
+		kept := make([]*CustomError, 0, len(errs))
+		for _, e := range errs {
+			if keep(e) {
+				kept = append(kept, e)
+			}
+		}
+		if len(kept) == 0 {
+			return nil
+		}
+		return sink.Send(ctx, kept)
+	})
+}
+
+// ExcludeTags returns a FilterSink predicate rejecting errors with any of tags in
+// their chain.
+func ExcludeTags(tags ...string) func(*CustomError) bool {
+	return func(e *CustomError) bool {
+		for _, tag := range tags {
+			if HasTag(e, tag) {
+				return false
+			}
+		}
+		return true
+	}
+}
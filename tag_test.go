@@ -0,0 +1,92 @@
+package exception
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestTags(t *testing.T) {
+	inner := New("query failed", ErrorInternalServer, WithTags("db", "transient", "db"))
+	tests := []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{"nil", nil, nil},
+		{"plain error", io.EOF, nil},
+		{"deduplicated", inner, []string{"db", "transient"}},
+		{"chain outermost first", Wrap(fmt.Errorf("load: %w", inner), WithTags("billing", "db")), []string{"billing", "db", "transient"}},
+		{"joined errors", errors.Join(New("a", ErrorNotFound, WithTags("billing")), Wrap(errors.Join(io.EOF, inner))),
+			[]string{"billing", "db", "transient"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tags(tt.err); !slices.Equal(got, tt.want) {
+				t.Fatalf("Tags = %q, want %q", got, tt.want)
+			}
+			for _, tag := range tt.want {
+				if !HasTag(tt.err, tag) {
+					t.Fatalf("HasTag(%q) = false", tag)
+				}
+			}
+			if HasTag(tt.err, "expected") {
+				t.Fatal(`HasTag("expected") = true`)
+			}
+		})
+	}
+	if got := inner.Tags(); !slices.Equal(got, []string{"db", "transient"}) {
+		t.Fatalf("CustomError.Tags = %q", got)
+	}
+}
+
+func TestMetricLabels(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want map[string]string
+	}{
+		{"nil", nil, nil},
+		{"plain error", io.EOF, map[string]string{LabelCode: "500", LabelDomain: "", LabelSeverity: "error", LabelTags: ""}},
+		{"custom error", New("missing", ErrorNotFound, WithDomain("users"), WithTags("transient", "db")),
+			map[string]string{LabelCode: "404", LabelDomain: "users", LabelSeverity: "warning", LabelTags: "db,transient"}},
+		{"fmt wrapped", fmt.Errorf("load: %w", New("boom", ErrorInternalServer, WithSeverity(SeverityCritical), WithTags("billing"))),
+			map[string]string{LabelCode: "500", LabelDomain: "", LabelSeverity: "critical", LabelTags: "billing"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MetricLabels(tt.err); !maps.Equal(got, tt.want) {
+				t.Fatalf("MetricLabels = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterSinkExcludeTags(t *testing.T) {
+	tagged := Wrap(New("inner", ErrorNotFound, WithTags("expected")), WithCode(ErrorNotFound)).(*CustomError)
+	plain := New("plain", ErrorInternalServer)
+	tests := []struct {
+		name    string
+		errs    []*CustomError
+		kept    []*CustomError
+		batches int
+	}{
+		{"mixed batch", []*CustomError{tagged, plain}, []*CustomError{plain}, 1},
+		{"nothing kept", []*CustomError{tagged}, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordingSink{}
+			if err := FilterSink(sink, ExcludeTags("expected")).Send(context.Background(), tt.errs); err != nil {
+				t.Fatal(err)
+			}
+			if got := sink.errs(); !slices.Equal(got, tt.kept) || len(sink.batches) != tt.batches {
+				t.Fatalf("sent %d batches of %v, want %d of %v", len(sink.batches), got, tt.batches, tt.kept)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package exception
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// DedupMode controls how repeated trace entries are collapsed.
+type DedupMode int
+
+const (
+	// DedupOff keeps every trace entry as captured.
+	DedupOff DedupMode = iota
+	// DedupOnWrap collapses a repeated entry into a counter when the error is wrapped.
+	DedupOnWrap
+	// DedupOnFormat keeps every entry but collapses repeats when the trace is printed.
+	DedupOnFormat
+)
+
+var (
+	traceDedupMode = DedupOff
+	stackDepth     = 1
+)
+
+// SetStackDepth sets the default number of frames captured each time an error is wrapped.
+// A capture point with several frames is recorded as one multi-line trace entry.
+// It should be called during initialization.
+func SetStackDepth(depth int) {
+	if depth < 1 {
+		depth = 1
+	}
+	stackDepth = depth
+}
+
+// Frame is a single capture point of a trace.
+type Frame struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function"`
+}
+
+func (f Frame) String() string {
+	if f.File == "" && f.Line == 0 {
+		return f.Function
+	}
+	return fmt.Sprintf("%s:%d %s", f.File, f.Line, f.Function)
+}
+
+// TracePoint is a capture point of a trace: the frames recorded where the error was
+// created or wrapped, and how many times in a row it was wrapped there.
+type TracePoint struct {
+	Frames []Frame `json:"frames"`
+	Count  int     `json:"count,omitempty"`
+}
+
+// IsZero reports whether f holds no location.
+func (f Frame) IsZero() bool {
+	return f == Frame{}
+}
+
+// parseFrame parses the first frame of a trace entry formatted as "file:line function".
+func parseFrame(trace string) Frame {
+	trace, _ = splitTraceCount(trace)
+	if i := strings.IndexByte(trace, '\n'); i >= 0 {
+		trace = trace[:i]
+	}
+	return parseFrameLine(trace)
+}
+
+// parseFrameLine parses a single "file:line function" line. A line in another
+// format, such as "unknown", is kept as the function name.
+func parseFrameLine(line string) Frame {
+	line = strings.TrimPrefix(line, "\t")
+	i := strings.LastIndex(line, " ")
+	if i < 0 {
+		return Frame{Function: line}
+	}
+	loc, function := line[:i], line[i+1:]
+	j := strings.LastIndex(loc, ":")
+	if j < 0 {
+		return Frame{Function: line}
+	}
+	n, err := strconv.Atoi(loc[j+1:])
+	if err != nil {
+		return Frame{Function: line}
+	}
+	return Frame{File: loc[:j], Line: n, Function: function}
+}
+
+// traceFrames splits a trace entry into its frames and occurrence counter.
+func traceFrames(trace string) ([]Frame, int) {
+	trace, count := splitTraceCount(trace)
+	if trace == "" {
+		return nil, count
+	}
+	lines := strings.Split(trace, "\n")
+	frames := make([]Frame, len(lines))
+	for i, line := range lines {
+		frames[i] = parseFrameLine(line)
+	}
+	return frames, count
+}
+
+// formatTrace formats frames and an occurrence counter as a trace entry.
+func formatTrace(frames []Frame, count int) string {
+	var sb strings.Builder
+	for _, frame := range frames {
+		if sb.Len() > 0 {
+			sb.WriteString("\n\t")
+		}
+		sb.WriteString(frame.String())
+	}
+	return joinTraceCount(sb.String(), count)
+}
+
+// SetTraceDedup sets how identical consecutive trace entries are collapsed into
+// "file:line function (xN)" entries. It should be called during initialization.
+func SetTraceDedup(mode DedupMode) {
+	traceDedupMode = mode
+}
+
+// splitTraceCount splits a trace entry into the entry without its occurrence
+// counter and the counter itself. The counter follows the entry's first frame.
+func splitTraceCount(trace string) (string, int) {
+	head, rest := trace, ""
+	if i := strings.IndexByte(trace, '\n'); i >= 0 {
+		head, rest = trace[:i], trace[i:]
+	}
+	if !strings.HasSuffix(head, ")") {
+		return trace, 1
+	}
+	i := strings.LastIndex(head, " (x")
+	if i < 0 {
+		return trace, 1
+	}
+	n, err := strconv.Atoi(head[i+3 : len(head)-1])
+	if err != nil || n < 1 {
+		return trace, 1
+	}
+	return head[:i] + rest, n
+}
+
+func joinTraceCount(trace string, n int) string {
+	if n <= 1 {
+		return trace
+	}
+	head, rest := trace, ""
+	if i := strings.IndexByte(trace, '\n'); i >= 0 {
+		head, rest = trace[:i], trace[i:]
+	}
+	return fmt.Sprintf("%s (x%d)%s", head, n, rest)
+}
+
+// dedupTraces collapses identical consecutive entries, summing their counters.
+func dedupTraces(traces []string) []string {
+	result := make([]string, 0, len(traces))
+	last, count := "", 0
+	for _, trace := range traces {
+		base, n := splitTraceCount(trace)
+		if count > 0 && base == last {
+			count += n
+			continue
+		}
+		if count > 0 {
+			result = append(result, joinTraceCount(last, count))
+		}
+		last, count = base, n
+	}
+	if count > 0 {
+		result = append(result, joinTraceCount(last, count))
+	}
+	return result
+}
+
+// maxInlineStackDepth is the largest depth captured without allocating the PC buffer.
+const maxInlineStackDepth = 64
+
+// maxFilteredFrames is how many frames beyond the depth are examined when frame
+// filters skip some of them.
+const maxFilteredFrames = 32
+
+// captureStackTrace records depth frames starting skip frames above the caller of
+// the exported wrap function; a depth of 0 selects the default set by SetStackDepth.
+// Frames rejected by the frame filters are skipped.
+func captureStackTrace(depth, skip int) string {
+	if depth < 1 {
+		depth = stackDepth
+	}
+	filters := frameFilters
+	capacity := depth
+	if len(filters) > 0 {
+		capacity += maxFilteredFrames
+	}
+	var buf [maxInlineStackDepth]uintptr
+	var pcs []uintptr
+	if capacity <= maxInlineStackDepth {
+		pcs = buf[:capacity]
+	} else {
+		pcs = make([]uintptr, capacity)
+	}
+	n := runtime.Callers(4+skip, pcs) // runtime.Callers, captureStackTrace, wrapError, 공개 함수를 건너뜀
+	if n == 0 {
+		return "unknown"
+	}
+	if len(filters) == 0 {
+		var sb strings.Builder
+		for _, pc := range pcs[:n] {
+			if sb.Len() > 0 {
+				sb.WriteString("\n\t")
+			}
+			sb.WriteString(symbols.lookup(pc).text)
+		}
+		return sb.String()
+	}
+	frames := make([]Frame, 0, depth)
+	for _, pc := range pcs[:n] {
+		for _, frame := range symbols.lookup(pc).frames {
+			if len(frames) < depth && !skipFrame(filters, frame) {
+				frames = append(frames, frame)
+			}
+		}
+	}
+	if len(frames) == 0 {
+		// 모든 프레임이 걸러진 경우 호출 위치를 그대로 기록
+		frames = symbols.lookup(pcs[0]).frames[:1]
+	}
+	return formatTrace(frames, 1)
+}
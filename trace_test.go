@@ -0,0 +1,235 @@
+package exception
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func setTraceDedup(t *testing.T, mode DedupMode) {
+	t.Helper()
+	prev := traceDedupMode
+	SetTraceDedup(mode)
+	t.Cleanup(func() { SetTraceDedup(prev) })
+}
+
+func TestSplitTraceCount(t *testing.T) {
+	tests := []struct {
+		trace string
+		base  string
+		n     int
+	}{
+		{"/app/main.go:10 main.load", "/app/main.go:10 main.load", 1},
+		{"/app/main.go:10 main.load (x3)", "/app/main.go:10 main.load", 3},
+		{"/app/main.go:10 main.load (x0)", "/app/main.go:10 main.load (x0)", 1},
+		{"/app/main.go:10 main.(*T).load (xy)", "/app/main.go:10 main.(*T).load (xy)", 1},
+	}
+	for _, tt := range tests {
+		if base, n := splitTraceCount(tt.trace); base != tt.base || n != tt.n {
+			t.Fatalf("splitTraceCount(%q) = %q, %d, want %q, %d", tt.trace, base, n, tt.base, tt.n)
+		}
+	}
+}
+
+func TestDedupTraces(t *testing.T) {
+	tests := []struct {
+		name   string
+		traces []string
+		want   []string
+	}{
+		{"empty", nil, []string{}},
+		{"distinct", []string{"a.go:1 f", "b.go:2 g"}, []string{"a.go:1 f", "b.go:2 g"}},
+		{"repeated", []string{"a.go:1 f", "a.go:1 f", "b.go:2 g"}, []string{"a.go:1 f (x2)", "b.go:2 g"}},
+		{"counters summed", []string{"a.go:1 f (x2)", "a.go:1 f", "a.go:1 f (x3)"}, []string{"a.go:1 f (x6)"}},
+		{"not consecutive", []string{"a.go:1 f", "b.go:2 g", "a.go:1 f"}, []string{"a.go:1 f", "b.go:2 g", "a.go:1 f"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dedupTraces(tt.traces); !slices.Equal(got, tt.want) {
+				t.Fatalf("dedupTraces = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func wrapInLoop(n int) error {
+	var err error = io.EOF
+	for range n {
+		err = WrapMessage(err, "retry")
+	}
+	return err
+}
+
+func TestTraceDedupModes(t *testing.T) {
+	tests := []struct {
+		mode     DedupMode
+		previous int
+		printed  int
+		suffix   string
+	}{
+		{DedupOff, 2, 3, ""},
+		{DedupOnWrap, 0, 1, " (x3)"},
+		{DedupOnFormat, 2, 1, " (x3)"},
+	}
+	for _, tt := range tests {
+		setTraceDedup(t, tt.mode)
+		customErr := wrapInLoop(3).(*CustomError)
+		if len(customErr.PreviousTraces) != tt.previous {
+			t.Fatalf("mode %d: PreviousTraces = %q", tt.mode, customErr.PreviousTraces)
+		}
+		lines := strings.Split(customErr.PrintTrace(), "\n")
+		if len(lines) != tt.printed || !strings.HasSuffix(lines[0], tt.suffix) {
+			t.Fatalf("mode %d: PrintTrace = %q", tt.mode, lines)
+		}
+	}
+}
+
+func TestParseFrame(t *testing.T) {
+	tests := []struct {
+		trace string
+		want  Frame
+	}{
+		{"/app/user.go:42 app.load", Frame{File: "/app/user.go", Line: 42, Function: "app.load"}},
+		{"/app/user.go:42 app.load (x3)", Frame{File: "/app/user.go", Line: 42, Function: "app.load"}},
+		{"C:/app/user.go:42 app.load", Frame{File: "C:/app/user.go", Line: 42, Function: "app.load"}},
+		{"/app/user.go:42 app.load\n\t/app/main.go:7 main.main", Frame{File: "/app/user.go", Line: 42, Function: "app.load"}},
+		{"unknown", Frame{Function: "unknown"}},
+		{"/app/user.go app.load", Frame{Function: "/app/user.go app.load"}},
+		{"/app/user.go:x app.load", Frame{Function: "/app/user.go:x app.load"}},
+		{"", Frame{}},
+	}
+	for _, tt := range tests {
+		got := parseFrame(tt.trace)
+		if got != tt.want {
+			t.Errorf("parseFrame(%q) = %+v, want %+v", tt.trace, got, tt.want)
+		}
+		if got.IsZero() != (tt.want == Frame{}) {
+			t.Errorf("parseFrame(%q).IsZero() = %v", tt.trace, got.IsZero())
+		}
+		if want, _, _ := strings.Cut(strings.TrimSuffix(tt.trace, " (x3)"), "\n"); got.String() != want {
+			t.Errorf("String() = %q, want %q", got.String(), want)
+		}
+	}
+}
+
+func setStackDepth(t *testing.T, depth int) {
+	t.Helper()
+	prev := stackDepth
+	SetStackDepth(depth)
+	t.Cleanup(func() { SetStackDepth(prev) })
+}
+
+func frameCount(trace string) int {
+	return strings.Count(trace, "\n") + 1
+}
+
+func TestStackDepth(t *testing.T) {
+	tests := []struct {
+		name    string
+		def     int
+		opts    []CustomErrorOption
+		want    int
+		atLeast bool
+	}{
+		{"default", 1, nil, 1, false},
+		{"option", 1, []CustomErrorOption{WithStackDepth(2)}, 2, false},
+		{"configured default", 2, nil, 2, false},
+		{"option overrides default", 3, []CustomErrorOption{WithStackDepth(1)}, 1, false},
+		{"invalid default", 0, nil, 1, false},
+		{"beyond inline buffer", 1, []CustomErrorOption{WithStackDepth(maxInlineStackDepth + 1)}, 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setStackDepth(t, tt.def)
+			customErr := Wrap(io.EOF, tt.opts...).(*CustomError)
+			n := frameCount(customErr.Trace)
+			if n != tt.want && !(tt.atLeast && n >= tt.want) {
+				t.Fatalf("captured %d frames, want %d: %q", n, tt.want, customErr.Trace)
+			}
+			if got := customErr.LatestTrace(); parseFrame(got).Function != "github.com/tae2089/exception.TestStackDepth.func1" {
+				t.Fatalf("first frame = %q", got)
+			}
+		})
+	}
+}
+
+func TestStackDepthAppliesToOneWrap(t *testing.T) {
+	customErr := Wrap(io.EOF, WithStackDepth(3)).(*CustomError)
+	Wrap(customErr)
+	if n := frameCount(customErr.Trace); n != 1 {
+		t.Fatalf("second wrap captured %d frames, want the default 1", n)
+	}
+	if n := frameCount(customErr.PreviousTraces[0]); n != 3 {
+		t.Fatalf("first wrap captured %d frames, want 3", n)
+	}
+}
+
+// wrapForCaller wraps err on behalf of its caller.
+func wrapForCaller(err error, skip int) error {
+	return Wrap(err, WithCallerSkip(skip))
+}
+
+func TestCallerSkip(t *testing.T) {
+	tests := []struct {
+		name string
+		skip int
+		want string
+	}{
+		{"helper frame", 0, "github.com/tae2089/exception.wrapForCaller"},
+		{"caller frame", 1, "github.com/tae2089/exception.TestCallerSkip.func1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customErr := wrapForCaller(io.EOF, tt.skip).(*CustomError)
+			if got := customErr.Origin().Function; got != tt.want {
+				t.Fatalf("origin = %q, want %q", got, tt.want)
+			}
+			// 다음 Wrap에는 적용되지 않아야 함
+			Wrap(customErr)
+			if got := parseFrame(customErr.LatestTrace()).Function; got != "github.com/tae2089/exception.TestCallerSkip.func1" {
+				t.Fatalf("second wrap frame = %q", got)
+			}
+		})
+	}
+}
+
+func TestTraceCountWithSeveralFrames(t *testing.T) {
+	trace := "a.go:1 f\n\tb.go:2 g"
+	counted := joinTraceCount(trace, 2)
+	if counted != "a.go:1 f (x2)\n\tb.go:2 g" {
+		t.Fatalf("joinTraceCount = %q", counted)
+	}
+	if base, n := splitTraceCount(counted); base != trace || n != 2 {
+		t.Fatalf("splitTraceCount = %q, %d", base, n)
+	}
+	if got := parseFrame(counted); got != (Frame{File: "a.go", Line: 1, Function: "f"}) {
+		t.Fatalf("parseFrame = %+v", got)
+	}
+}
+
+func TestTraceFramesRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		trace  string
+		frames []Frame
+		count  int
+	}{
+		{"empty", "", nil, 1},
+		{"one frame", "a.go:1 f", []Frame{{File: "a.go", Line: 1, Function: "f"}}, 1},
+		{"counted", "a.go:1 f (x3)\n\tb.go:2 g", []Frame{{File: "a.go", Line: 1, Function: "f"}, {File: "b.go", Line: 2, Function: "g"}}, 3},
+		{"unparsable", "unknown", []Frame{{Function: "unknown"}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frames, count := traceFrames(tt.trace)
+			if fmt.Sprint(frames) != fmt.Sprint(tt.frames) || count != tt.count {
+				t.Fatalf("traceFrames = %v, %d, want %v, %d", frames, count, tt.frames, tt.count)
+			}
+			if got := formatTrace(frames, count); got != tt.trace {
+				t.Fatalf("formatTrace = %q, want %q", got, tt.trace)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package exception
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Field keys attached to errors converted by Transport and FromHTTPResponse.
+const (
+	FieldHTTPMethod = "http.method"
+	FieldHTTPURL    = "http.url"
+	FieldHTTPHost   = "http.host"
+	FieldHTTPStatus = "http.status"
+)
+
+// maxResponseBody bounds how much of an error response FromHTTPResponse reads.
+const maxResponseBody = 64 << 10
+
+// RoundTripper converts the failures of an underlying http.RoundTripper into
+// CustomErrors; see Transport.
+type RoundTripper struct {
+	// Next performs the requests. nil uses http.DefaultTransport.
+	Next http.RoundTripper
+	// ConvertResponses makes 4xx and 5xx responses fail with the error returned
+	// by FromHTTPResponse instead of being returned.
+	ConvertResponses bool
+}
+
+// Transport wraps next so that DNS, TLS, connection and timeout failures are
+// returned as coded CustomErrors with the method, URL and host as fields:
+// timeouts become ErrorGatewayTimeout, refused or reset connections and temporary
+// DNS failures ErrorServiceUnavailable, and the remaining failures
+// ErrorBadGateway, retryable unless they are DNS or certificate errors that would
+// fail again. Canceled requests become ErrorClientClosedRequest.
+func Transport(next http.RoundTripper) *RoundTripper {
+	return &RoundTripper{Next: next}
+}
+
+func (t *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		code, retryable := classifyTransportError(err)
+		return nil, wrapError(err,
+			WithMessage(err.Error()),
+			WithCode(code),
+			WithRetryable(retryable),
+			WithFields(requestFields(req)),
+		)
+	}
+	if t.ConvertResponses && resp.StatusCode >= http.StatusBadRequest {
+		respErr := FromHTTPResponse(resp)
+		resp.Body.Close()
+		return nil, respErr
+	}
+	return resp, nil
+}
+
+func requestFields(req *http.Request) map[string]any {
+	return map[string]any{
+		FieldHTTPMethod: req.Method,
+		FieldHTTPURL:    req.URL.Redacted(),
+		FieldHTTPHost:   req.URL.Host,
+	}
+}
+
+// classifyTransportError returns the code of a transport failure and whether
+// retrying it may succeed.
+func classifyTransportError(err error) (ErrorCode, bool) {
+	var (
+		dnsErr       *net.DNSError
+		netErr       net.Error
+		opErr        *net.OpError
+		certErr      *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+		recordErr    tls.RecordHeaderError
+	)
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrorClientClosedRequest, false
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorGatewayTimeout, true
+	case errors.As(err, &dnsErr):
+		if dnsErr.IsTimeout || dnsErr.IsTemporary {
+			return ErrorServiceUnavailable, true
+		}
+		return ErrorBadGateway, false
+	case errors.As(err, &certErr), errors.As(err, &authorityErr), errors.As(err, &hostnameErr),
+		errors.As(err, &invalidErr), errors.As(err, &recordErr):
+		return ErrorBadGateway, false
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorGatewayTimeout, true
+	case errors.As(err, &opErr):
+		return ErrorServiceUnavailable, true
+	}
+	return ErrorBadGateway, true
+}
+
+// FromHTTPResponse converts a 4xx or 5xx response into a remote CustomError,
+// returning nil for other responses. The code, ID and domain are read from the
+// headers written by SetHTTPHeaders, and the message from a body written by
+// WriteHTTP, falling back to the status code and the default message. A
+// Retry-After header in seconds sets the retry delay. Up to 64 KiB of the body is
+// read; resp.Body is replaced so that it can still be read from the start.
+func FromHTTPResponse(resp *http.Response) *CustomError {
+	if resp == nil || resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	var body responseBody
+	if resp.Body != nil {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+		_ = json.Unmarshal(data, &body)
+	}
+	code := ErrorCode(resp.StatusCode)
+	if n, err := strconv.Atoi(resp.Header.Get(HeaderErrorCode)); err == nil {
+		code = ErrorCode(n)
+	} else if body.Code != 0 {
+		code = body.Code
+	}
+	opts := []CustomErrorOption{WithRemote(), WithField(FieldHTTPStatus, resp.StatusCode)}
+	if resp.Request != nil {
+		opts = append(opts, WithFields(requestFields(resp.Request)))
+	}
+	if id := resp.Header.Get(HeaderErrorID); id != "" {
+		opts = append(opts, WithID(id))
+	} else if body.ID != "" {
+		opts = append(opts, WithID(body.ID))
+	}
+	if domain := resp.Header.Get(HeaderErrorDomain); domain != "" {
+		opts = append(opts, WithDomain(domain))
+	} else if body.Domain != "" {
+		opts = append(opts, WithDomain(body.Domain))
+	}
+	if body.Hint != "" {
+		opts = append(opts, WithHint(body.Hint))
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		opts = append(opts, WithRetryAfter(time.Duration(seconds)*time.Second))
+	}
+	return New(body.Message, code, opts...)
+}
@@ -0,0 +1,134 @@
+package exception
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxWebhookDrain is how much of a response body is read so the connection can
+// be reused.
+const maxWebhookDrain = 64 << 10
+
+// HeaderWebhookSignature carries the HMAC-SHA256 of a webhook request body, as
+// "sha256=<hex>", when WebhookOptions.Secret is set.
+const HeaderWebhookSignature = "X-Exception-Signature"
+
+// WebhookOptions configures a WebhookSink.
+type WebhookOptions struct {
+	// URL is the endpoint the reports are POSTed to.
+	URL string
+	// Secret, if not empty, signs each request body; see HeaderWebhookSignature.
+	Secret []byte
+	// Header is added to every request, e.g. for an authorization token.
+	Header http.Header
+	// Client sends the requests. The default is a client with a 10 second timeout.
+	Client *http.Client
+	// Retries is how many times a failed request is retried. The default is 3; a
+	// negative value disables retries.
+	Retries int
+	// Backoff is the delay before the first retry, doubled for each further one.
+	// The default is 500 milliseconds.
+	Backoff time.Duration
+}
+
+// WebhookSink is a Sink POSTing reported errors as JSON to an HTTP endpoint, such
+// as a chat bridge or internal incident tooling. The body is an object whose
+// "errors" member lists each error's fingerprint and its Encode representation.
+// Requests failing with a network error, 429 or a 5xx status are retried.
+type WebhookSink struct {
+	opts WebhookOptions
+}
+
+type webhookPayload struct {
+	Errors []webhookError `json:"errors"`
+}
+
+type webhookError struct {
+	Fingerprint string          `json:"fingerprint"`
+	Error       json.RawMessage `json:"error"`
+}
+
+// NewWebhookSink creates a sink posting to opts.URL.
+func NewWebhookSink(opts WebhookOptions) *WebhookSink {
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if opts.Retries == 0 {
+		opts.Retries = 3
+	} else if opts.Retries < 0 {
+		opts.Retries = 0
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = 500 * time.Millisecond
+	}
+	return &WebhookSink{opts: opts}
+}
+
+func (s *WebhookSink) Send(ctx context.Context, errs []*CustomError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	payload := webhookPayload{Errors: make([]webhookError, 0, len(errs))}
+	for _, e := range errs {
+		data, err := Encode(e)
+		if err != nil {
+			return err
+		}
+		payload.Errors = append(payload.Errors, webhookError{Fingerprint: e.Fingerprint(), Error: data})
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	delay := s.opts.Backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(ctx, body)
+		if err == nil || !retry || attempt >= s.opts.Retries {
+			return err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+		delay *= 2
+	}
+}
+
+// post sends body once and reports whether a failure is worth retrying.
+func (s *WebhookSink) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	for key, values := range s.opts.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(s.opts.Secret) > 0 {
+		mac := hmac.New(sha256.New, s.opts.Secret)
+		mac.Write(body)
+		req.Header.Set(HeaderWebhookSignature, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxWebhookDrain))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("exception: webhook %s returned %s", s.opts.URL, resp.Status)
+}
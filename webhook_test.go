@@ -0,0 +1,147 @@
+package exception
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookServer answers with the given statuses in turn, repeating the last one,
+// and records when each request arrived.
+type webhookServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	times    []time.Time
+	bodies   [][]byte
+	headers  []http.Header
+}
+
+func newWebhookServer(t *testing.T, statuses ...int) *webhookServer {
+	s := &webhookServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		n := len(s.times)
+		s.times = append(s.times, time.Now())
+		s.bodies = append(s.bodies, body)
+		s.headers = append(s.headers, r.Header.Clone())
+		status := s.statuses[min(n, len(s.statuses)-1)]
+		s.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *webhookServer) requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.times)
+}
+
+func TestWebhookSinkRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		retries  int
+		statuses []int
+		requests int
+		fails    bool
+	}{
+		{"success", 0, []int{http.StatusOK}, 1, false},
+		{"retried until success", 0, []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusNoContent}, 3, false},
+		{"default retries exhausted", 0, []int{http.StatusInternalServerError}, 4, true},
+		{"custom retries", 1, []int{http.StatusInternalServerError}, 2, true},
+		{"retries disabled", -1, []int{http.StatusInternalServerError}, 1, true},
+		{"client error not retried", 0, []int{http.StatusBadRequest}, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newWebhookServer(t, tt.statuses...)
+			sink := NewWebhookSink(WebhookOptions{URL: srv.URL, Retries: tt.retries, Backoff: time.Millisecond})
+			err := sink.Send(context.Background(), []*CustomError{New("boom", ErrorInternalServer)})
+			if (err != nil) != tt.fails {
+				t.Fatalf("Send = %v, want failure %v", err, tt.fails)
+			}
+			if got := srv.requests(); got != tt.requests {
+				t.Fatalf("sent %d requests, want %d", got, tt.requests)
+			}
+		})
+	}
+}
+
+func TestWebhookSinkBackoff(t *testing.T) {
+	srv := newWebhookServer(t, http.StatusBadGateway)
+	const backoff = 20 * time.Millisecond
+	sink := NewWebhookSink(WebhookOptions{URL: srv.URL, Retries: 3, Backoff: backoff})
+	if err := sink.Send(context.Background(), []*CustomError{New("boom", ErrorInternalServer)}); err == nil {
+		t.Fatal("Send should fail")
+	}
+	// 재시도 간격은 매번 두 배로 늘어남
+	for i, want := range []time.Duration{backoff, 2 * backoff, 4 * backoff} {
+		if got := srv.times[i+1].Sub(srv.times[i]); got < want {
+			t.Errorf("delay before retry %d = %s, want at least %s", i+1, got, want)
+		}
+	}
+}
+
+func TestWebhookSinkStopsOnCancel(t *testing.T) {
+	srv := newWebhookServer(t, http.StatusServiceUnavailable)
+	sink := NewWebhookSink(WebhookOptions{URL: srv.URL, Backoff: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := sink.Send(ctx, []*CustomError{New("boom", ErrorInternalServer)}); err != context.DeadlineExceeded {
+		t.Fatalf("Send = %v, want the context error", err)
+	}
+	if got := srv.requests(); got != 1 {
+		t.Fatalf("sent %d requests, want 1", got)
+	}
+}
+
+func TestWebhookSinkRequest(t *testing.T) {
+	srv := newWebhookServer(t, http.StatusOK)
+	secret := []byte("s3cret")
+	sink := NewWebhookSink(WebhookOptions{URL: srv.URL, Secret: secret, Header: http.Header{"Authorization": {"Bearer token"}}})
+	errs := []*CustomError{New("boom", ErrorInternalServer), New("missing", ErrorNotFound)}
+	if err := sink.Send(context.Background(), errs); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Send(context.Background(), nil); err != nil || srv.requests() != 1 {
+		t.Fatalf("an empty batch should not be sent: %v", err)
+	}
+	body, header := srv.bodies[0], srv.headers[0]
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"Content-Type", "application/json"},
+		{"Authorization", "Bearer token"},
+		{HeaderWebhookSignature, "sha256=" + hex.EncodeToString(mac.Sum(nil))},
+	}
+	for _, tt := range tests {
+		if got := header.Get(tt.header); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if len(payload.Errors) != 2 || payload.Errors[0].Fingerprint != errs[0].Fingerprint() {
+		t.Fatalf("payload = %s", body)
+	}
+	decoded, err := Decode(payload.Errors[1].Error)
+	if err != nil || decoded.Code() != ErrorNotFound {
+		t.Fatalf("decoded %v, %v", decoded, err)
+	}
+}